@@ -0,0 +1,227 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// DefaultAuditTable is the default audit table when CRUD.AuditTable is empty, the table should having columns
+// table_name,pk,actor,op,diff,create_time
+const DefaultAuditTable = "crud_audit"
+
+var auditFromRegexp = regexp.MustCompile(`(?is)^\s*(?:update\s+(.+?)\s+set\s|delete\s+from\s+(.+)$)`)
+
+type actorContextKey struct{}
+
+// WithActor will return the context having actor, it is recorded to audit table by update/delete on audit model
+func WithActor(ctx context.Context, actor interface{}) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFrom will return the actor by WithActor
+func ActorFrom(ctx context.Context) (actor interface{}, ok bool) {
+	if ctx == nil {
+		return
+	}
+	actor = ctx.Value(actorContextKey{})
+	ok = actor != nil
+	return
+}
+
+// AuditDiff is the changed column value of audit record
+type AuditDiff struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// auditModel will return the model and primary key by audit:"tid" tag on model when op is update/delete, nil is returned when not audit model
+func (c *CRUD) auditModel(ctx context.Context, op string, v interface{}) (model interface{}, pk string) {
+	if op != OpUpdate && op != OpDelete {
+		return
+	}
+	model = v
+	if pk = c.modelTag(model, "audit"); len(pk) < 1 {
+		model = nil
+	}
+	return
+}
+
+func (c *CRUD) auditTable() string {
+	if len(c.AuditTable) > 0 {
+		return c.AuditTable
+	}
+	return DefaultAuditTable
+}
+
+// auditWhere will renumber the placeholder of joined where from 1 and return the args of it
+func (c *CRUD) auditWhere(where string, args []interface{}) (where_ string, whereArgs []interface{}) {
+	begin := 0
+	positions, indexes := c.placeholderAll(where, len(args))
+	for i, position := range positions {
+		index := indexes[i]
+		if index < 0 || index >= len(args) {
+			continue
+		}
+		whereArgs = append(whereArgs, args[index])
+		where_ += where[begin:position] + c.placeholder(len(whereArgs))
+		begin = position + len(c.placeholder(index+1))
+	}
+	where_ += where[begin:]
+	return
+}
+
+// auditFrom will return the table and alias of update/delete sql head like update crud_object o set or delete from crud_object o
+func (c *CRUD) auditFrom(sql string) (from string) {
+	if match := auditFromRegexp.FindStringSubmatch(sql); match != nil {
+		from = strings.TrimSpace(match[1] + match[2])
+	}
+	return
+}
+
+// auditRows will query rows of model by where and return the map of primary key to row
+func (c *CRUD) auditRows(caller int, queryer interface{}, ctx context.Context, model interface{}, pk, from, where string, args []interface{}, lock bool) (keys []string, rows map[string]reflect.Value, err error) {
+	sql := c.querySQL(caller+1, model, from, "#all")
+	if len(where) > 0 {
+		sql += " where " + where
+	}
+	if lock && (c.Dialect == nil || c.Dialect.Name() != "sqlite") {
+		sql += " for update"
+	}
+	modelType := reflect.Indirect(reflect.ValueOf(model)).Type()
+	list := reflect.New(reflect.SliceOf(reflect.PtrTo(modelType)))
+	err = c.query(caller+1, queryer, ctx, model, "#all", sql, args, list.Interface())
+	if err != nil {
+		return
+	}
+	rows = map[string]reflect.Value{}
+	for i := 0; i < list.Elem().Len(); i++ {
+		row := list.Elem().Index(i).Elem()
+		key := fmt.Sprintf("%v", c.auditPK(row, pk))
+		keys = append(keys, key)
+		rows[key] = row
+	}
+	return
+}
+
+// auditPK will return the primary key value of row
+func (c *CRUD) auditPK(row reflect.Value, pk string) (value interface{}) {
+	meta := c.loadFilterMeta("query", row.Type(), pk+"#all")
+	if len(meta.Fields) > 0 {
+		value = row.FieldByIndex(meta.Fields[0].Index).Interface()
+	}
+	return
+}
+
+// auditDiff will return the changed column of before/after, after is invalid on real delete
+func (c *CRUD) auditDiff(before, after reflect.Value) (diff map[string]*AuditDiff) {
+	diff = map[string]*AuditDiff{}
+	meta := c.loadFilterMeta("query", before.Type(), "#all")
+	for _, field := range meta.Fields {
+		beforeValue := before.FieldByIndex(field.Index).Addr().Interface()
+		var afterValue interface{}
+		if after.IsValid() {
+			afterValue = after.FieldByIndex(field.Index).Addr().Interface()
+			if jsonString(beforeValue) == jsonString(afterValue) {
+				continue
+			}
+		}
+		if c.redactField(field.Name, field.Field) {
+			beforeValue = RedactMask
+			if afterValue != nil {
+				afterValue = RedactMask
+			}
+		}
+		diff[field.Name] = &AuditDiff{
+			Before: beforeValue,
+			After:  afterValue,
+		}
+	}
+	return
+}
+
+// auditTx will return if the audit can be run in transaction by queryer is CrudBeginner/CrudTx or ctx is in WithTx
+func (c *CRUD) auditTx(queryer interface{}, ctx context.Context) bool {
+	if ctx != nil {
+		if _, ok := ctx.Value(txContextKey{}).(*txState); ok {
+			return true
+		}
+	}
+	switch c.queryerResolve(queryer, ctx).(type) {
+	case CrudTx, CrudBeginner:
+		return true
+	default:
+		return false
+	}
+}
+
+// auditExec will run exec in transaction, the rows is read by for update before exec with the where of exec and the audit record is appended after exec,
+// the audit is best-effort without transaction and lock when queryer is not CrudBeginner/CrudTx and ctx is not in WithTx
+func (c *CRUD) auditExec(queryer interface{}, ctx context.Context, op string, v interface{}, head, sql, where string, args []interface{}, model interface{}, pk string) (affected int64, err error) {
+	table := c.TablePrefix + c.modelTag(model, "table")
+	actor, _ := ActorFrom(ctx)
+	call := func(ctx context.Context, tx interface{}, lock bool) (err error) {
+		auditWhere, auditArgs := c.auditWhere(where, args)
+		keys, before, err := c.auditRows(1, tx, ctx, model, pk, c.auditFrom(head), auditWhere, auditArgs, lock)
+		if err != nil {
+			return
+		}
+		_, affected, err = c.queryerExec(tx, ctx, op, v, sql, args)
+		if err != nil || len(keys) < 1 {
+			return
+		}
+		after := map[string]reflect.Value{}
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(head)), "update") {
+			var params []string
+			var keyArgs []interface{}
+			for _, key := range keys {
+				keyArgs = append(keyArgs, c.auditPK(before[key], pk))
+				params = append(params, c.placeholder(len(keyArgs)))
+			}
+			_, after, err = c.auditRows(1, tx, ctx, model, pk, "", fmt.Sprintf("%v in (%v)", c.quote(pk), strings.Join(params, ",")), keyArgs, false)
+			if err != nil {
+				return
+			}
+		}
+		recordSQL := fmt.Sprintf(`insert into %v(table_name,pk,actor,op,diff,create_time) values(%v,%v,%v,%v,%v,%v)`, c.quoteTable(c.auditTable()), c.placeholder(1), c.placeholder(2), c.placeholder(3), c.placeholder(4), c.placeholder(5), c.placeholder(6))
+		for _, key := range keys {
+			diff := c.auditDiff(before[key], after[key])
+			if len(diff) < 1 {
+				continue
+			}
+			actorValue := ""
+			if actor != nil {
+				actorValue = fmt.Sprintf("%v", actor)
+			}
+			_, _, err = c.queryerExec(tx, ctx, OpInsert, nil, recordSQL, []interface{}{table, key, actorValue, op, jsonString(diff), c.now()})
+			if err != nil {
+				return
+			}
+		}
+		return
+	}
+	if c.auditTx(queryer, ctx) {
+		err = c.withTx(2, ctx, queryer, nil, func(ctx context.Context, tx Queryer) error {
+			return call(ctx, tx, true)
+		})
+	} else {
+		err = call(ctx, queryer, false)
+	}
+	if c.Verbose {
+		c.Log(1, "CRUD audit %v by struct:%v,sql:%v, result is affected:%v,err:%v", op, reflect.TypeOf(v), sql, affected, err)
+	}
+	return
+}
+
+// execWhere will join where to sql and exec it, the rows matched by where is audited when v is audit model
+func (c *CRUD) execWhere(caller int, queryer interface{}, ctx context.Context, op string, v interface{}, sql string, where []string, sep string, args []interface{}) (sql_ string, affected int64, err error) {
+	sql_ = c.joinWhere(caller+1, sql, where, sep)
+	if model, pk := c.auditModel(ctx, op, v); model != nil {
+		affected, err = c.auditExec(queryer, ctx, op, v, sql, sql_, strings.Join(where, " "+sep+" "), args, model, pk)
+		return
+	}
+	_, affected, err = c.queryerExec(queryer, ctx, op, v, sql_, args)
+	return
+}
@@ -0,0 +1,202 @@
+package crud
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type AuditCrudObject struct {
+	T      string           `json:"-" table:"crud_object" audit:"tid"`
+	TID    int64            `json:"tid,omitempty"`
+	UserID int64            `json:"user_id,omitempty"`
+	Title  string           `json:"title,omitempty"`
+	Image  string           `json:"image,omitempty" redact:"true"`
+	Status CrudObjectStatus `json:"status,omitempty"`
+}
+
+type auditTx struct {
+	*sql.Tx
+}
+
+func (a *auditTx) Exec(ctx context.Context, query string, args ...interface{}) (insertId, affected int64, err error) {
+	res, err := a.Tx.ExecContext(ctx, query, args...)
+	if err == nil {
+		insertId, _ = res.LastInsertId()
+		affected, err = res.RowsAffected()
+	}
+	return
+}
+
+func (a *auditTx) ExecRow(ctx context.Context, query string, args ...interface{}) (insertId int64, err error) {
+	insertId, affected, err := a.Exec(ctx, query, args...)
+	if err == nil && affected < 1 {
+		err = ErrNoRows
+	}
+	return
+}
+
+func (a *auditTx) Query(ctx context.Context, query string, args ...interface{}) (rows Rows, err error) {
+	rows, err = a.Tx.QueryContext(ctx, query, args...)
+	return
+}
+
+func (a *auditTx) QueryRow(ctx context.Context, query string, args ...interface{}) (row Row) {
+	row = a.Tx.QueryRowContext(ctx, query, args...)
+	return
+}
+
+func (a *auditTx) CrudCommit(ctx context.Context) error { return a.Tx.Commit() }
+
+func (a *auditTx) CrudRollback(ctx context.Context) error { return a.Tx.Rollback() }
+
+type auditBeginner struct {
+	*TestDbQueryer
+}
+
+func (a *auditBeginner) CrudBegin(ctx context.Context, opts *sql.TxOptions) (tx CrudTx, err error) {
+	sqlTx, err := a.DB.BeginTx(ctx, opts)
+	if err == nil {
+		tx = &auditTx{Tx: sqlTx}
+	}
+	return
+}
+
+func TestAuditWhere(t *testing.T) {
+	c := NewCRUD(DialectPostgres)
+	where, args := c.auditWhere("tid=$2 and level=$3", []interface{}{"a", 1, 3})
+	if where != "tid=$1 and level=$2" || fmt.Sprintf("%v", args) != "[1 3]" {
+		t.Error(where, args)
+		return
+	}
+	where, args = c.auditWhere("(tid=$1 or title=$2) and user_id=$4", []interface{}{1, "a", "b", 100})
+	if where != "(tid=$1 or title=$2) and user_id=$3" || fmt.Sprintf("%v", args) != "[1 a 100]" {
+		t.Error(where, args)
+		return
+	}
+	where, args = c.auditWhere("o.tid=$2 and o.title<>'a where b'", []interface{}{"a", 1})
+	if where != "o.tid=$1 and o.title<>'a where b'" || fmt.Sprintf("%v", args) != "[1]" {
+		t.Error(where, args)
+		return
+	}
+	where, args = c.auditWhere("", nil)
+	if where != "" || len(args) != 0 {
+		t.Error(where, args)
+		return
+	}
+	c = NewCRUD(DialectMySQL)
+	where, args = c.auditWhere("tid=? and level=?", []interface{}{"a", 1, 3})
+	if where != "tid=? and level=?" || fmt.Sprintf("%v", args) != "[1 3]" {
+		t.Error(where, args)
+		return
+	}
+	for head, from := range map[string]string{
+		"update crud_object set title=$1":                                              "crud_object",
+		"UPDATE crud_object o\nSET title=(select title from crud_object where tid=$1)": "crud_object o",
+		"delete from crud_object":                                                      "crud_object",
+		"delete from crud_object o":                                                    "crud_object o",
+		"select 1":                                                                     "",
+	} {
+		if having := c.auditFrom(head); having != from {
+			t.Error(head, having)
+		}
+	}
+}
+
+func TestAuditDiff(t *testing.T) {
+	c := NewCRUD(DialectPostgres)
+	before := &AuditCrudObject{TID: 1, Title: "a", Image: "secret", Status: CrudObjectStatusNormal}
+	after := &AuditCrudObject{TID: 1, Title: "b", Image: "changed", Status: CrudObjectStatusNormal}
+	diff := c.auditDiff(reflect.ValueOf(before).Elem(), reflect.ValueOf(after).Elem())
+	if data := jsonString(diff); data != `{"image":{"before":"******","after":"******"},"title":{"before":"a","after":"b"}}` {
+		t.Error(data)
+		return
+	}
+	diff = c.auditDiff(reflect.ValueOf(before).Elem(), reflect.Value{})
+	if len(diff) != 5 || diff["title"].After != nil {
+		t.Error(jsonString(diff))
+		return
+	}
+	if model, pk := c.auditModel(context.Background(), OpUpdate, before); model == nil || pk != "tid" {
+		t.Error(model, pk)
+		return
+	}
+	if model, _ := c.auditModel(context.Background(), OpInsert, before); model != nil {
+		t.Error(model)
+		return
+	}
+	if model, _ := c.auditModel(context.Background(), OpUpdate, &CrudObject{}); model != nil {
+		t.Error(model)
+		return
+	}
+}
+
+func TestAuditQuery(t *testing.T) {
+	clearPG()
+	testAuditQuery(t, getPG())
+}
+
+func testAuditQuery(t *testing.T, queryer *TestDbQueryer) {
+	ctx := WithActor(context.Background(), "alice")
+	_, _, err := queryer.Exec(ctx, `create table if not exists crud_audit(table_name text, pk text, actor text, op text, diff text, create_time timestamp with time zone)`)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	queryer.Exec(ctx, `delete from crud_audit`)
+	beginner := &auditBeginner{TestDbQueryer: queryer}
+	objects := []*CrudObject{}
+	for i := 0; i < 2; i++ {
+		object := newTestObject()
+		_, err = InsertFilter(queryer, ctx, object, "^tid#all", "returning", "tid#all")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		objects = append(objects, object)
+	}
+	lastAudit := func() (count int64, pk, actor, op string, diff map[string]*AuditDiff) {
+		queryer.QueryRow(ctx, `select count(*) from crud_audit`).Scan(&count)
+		var data string
+		queryer.QueryRow(ctx, `select pk,actor,op,diff from crud_audit order by create_time desc,op asc limit 1`).Scan(&pk, &actor, &op, &data)
+		json.Unmarshal([]byte(data), &diff)
+		return
+	}
+	err = UpdateRowWheref(beginner, ctx, &AuditCrudObject{Title: "changed"}, "title", "tid=$%v", objects[0].TID)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if count, pk, actor, op, diff := lastAudit(); count != 1 || pk != fmt.Sprintf("%v", objects[0].TID) || actor != "alice" || op != OpUpdate || len(diff) != 1 || diff["title"].Before != "title" || diff["title"].After != "changed" {
+		t.Error(count, pk, actor, op, diff)
+		return
+	}
+	err = UpdateRowWheref(beginner, ctx, &AuditCrudObject{Title: "changed"}, "title", "tid=$%v", objects[0].TID)
+	if count, _, _, _, _ := lastAudit(); err != nil || count != 1 {
+		t.Error(err, count)
+		return
+	}
+	_, err = DeleteWheref(beginner, ctx, &AuditCrudObject{}, "tid=$%v", objects[1].TID)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if count, _, _, op, diff := lastAudit(); count != 2 || op != OpDelete || diff["title"] == nil || diff["title"].After != nil || diff["image"].Before != RedactMask {
+		t.Error(count, op, diff)
+		return
+	}
+	_, err = DeleteWheref(beginner, ctx, &AuditCrudObject{}, "tid=$%v", -1)
+	if count, _, _, _, _ := lastAudit(); err != nil || count != 2 {
+		t.Error(err, count)
+		return
+	}
+	//best-effort without transaction
+	_, err = DeleteWheref(queryer, ctx, &AuditCrudObject{}, "tid=$%v", objects[0].TID)
+	if count, _, _, op, _ := lastAudit(); err != nil || count != 3 || op != OpDelete {
+		t.Error(err, count, op)
+		return
+	}
+}
@@ -0,0 +1,108 @@
+package crud
+
+import (
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// now will return the time by CRUD.Now, time.Now is used when it is nil
+func (c *CRUD) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// autoTimeFields will return the fields with autotime:"create" or autotime:"update" tag of v
+func (c *CRUD) autoTimeFields(v interface{}, kind string) (fields []*fieldMeta, values []reflect.Value) {
+	reflectValue := reflect.Indirect(reflect.ValueOf(v))
+	if reflectValue.Kind() != reflect.Struct || !reflectValue.CanAddr() {
+		return
+	}
+	meta := c.loadFilterMeta("update", reflectValue.Type(), "#all")
+	for _, field := range meta.Fields {
+		if field.Field.Tag.Get("autotime") == kind {
+			fields = append(fields, field)
+			values = append(values, reflectValue.FieldByIndex(field.Index))
+		}
+	}
+	return
+}
+
+// autoTimeInsert will fill the autotime create/update field of v by now when it is zero
+func (c *CRUD) autoTimeInsert(v interface{}) {
+	now := c.now()
+	for _, kind := range []string{"create", "update"} {
+		_, values := c.autoTimeFields(v, kind)
+		for _, value := range values {
+			c.autoTimeSet(value, now, false)
+		}
+	}
+}
+
+// autoTimeUpdate will set the autotime update field of v to now
+func (c *CRUD) autoTimeUpdate(v interface{}) {
+	now := c.now()
+	_, values := c.autoTimeFields(v, "update")
+	for _, value := range values {
+		c.autoTimeSet(value, now, true)
+	}
+}
+
+// autoTimeCopy will return the copy of v which the autotime field is setted like autoTimeInsert/autoTimeUpdate,
+// it is used by sql builder to get the autotime args without changing v, v is returned directly when not having autotime field
+func (c *CRUD) autoTimeCopy(v interface{}, insert, update bool) (copied interface{}) {
+	copied = v
+	reflectValue := reflect.ValueOf(v)
+	if reflectValue.Kind() != reflect.Ptr || reflectValue.IsNil() || reflectValue.Elem().Kind() != reflect.Struct {
+		return
+	}
+	creates, _ := c.autoTimeFields(v, "create")
+	updates, _ := c.autoTimeFields(v, "update")
+	if len(creates)+len(updates) < 1 {
+		return
+	}
+	copiedValue := reflect.New(reflectValue.Elem().Type())
+	copiedValue.Elem().Set(reflectValue.Elem())
+	for _, field := range append(creates, updates...) {
+		value := copiedValue.Elem().FieldByIndex(field.Index)
+		if value.Kind() == reflect.Ptr && !value.IsNil() {
+			elem := reflect.New(value.Type().Elem())
+			elem.Elem().Set(value.Elem())
+			value.Set(elem)
+		}
+	}
+	copied = copiedValue.Interface()
+	if insert {
+		c.autoTimeInsert(copied)
+	}
+	if update {
+		c.autoTimeUpdate(copied)
+	}
+	return
+}
+
+// autoTimeSet will set value to now, the value can be time.Time or convertible type like xsql.Time, int64 is set to timestamp in millisecond
+func (c *CRUD) autoTimeSet(value reflect.Value, now time.Time, force bool) {
+	if value.Kind() == reflect.Ptr {
+		if !value.IsNil() {
+			c.autoTimeSet(value.Elem(), now, force)
+			return
+		}
+		elem := reflect.New(value.Type().Elem())
+		c.autoTimeSet(elem.Elem(), now, true)
+		value.Set(elem)
+		return
+	}
+	if !force && !value.IsZero() {
+		return
+	}
+	switch {
+	case timeType.ConvertibleTo(value.Type()):
+		value.Set(reflect.ValueOf(now).Convert(value.Type()))
+	case value.CanInt():
+		value.SetInt(now.UnixNano() / 1e6)
+	}
+}
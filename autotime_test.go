@@ -0,0 +1,137 @@
+package crud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codingeasygo/util/xsql"
+)
+
+type AutoTimeCrudObject struct {
+	T          string           `json:"-" table:"crud_object"`
+	TID        int64            `json:"tid,omitempty"`
+	Title      string           `json:"title,omitempty"`
+	Int64Value int64            `json:"int64_value,omitempty" autotime:"create"`
+	TimeValue  xsql.Time        `json:"time_value,omitempty" autotime:"create"`
+	UpdateTime xsql.Time        `json:"update_time,omitempty" autotime:"update"`
+	CreateTime xsql.Time        `json:"create_time,omitempty" autotime:"create"`
+	Status     CrudObjectStatus `json:"status,omitempty"`
+}
+
+func TestAutoTime(t *testing.T) {
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.Local)
+	c := NewCRUD(DialectPostgres)
+	c.Now = func() time.Time { return now }
+	{ //insert
+		object := &AutoTimeCrudObject{Title: "abc", CreateTime: xsql.TimeUnix(1000)}
+		_, fields, _, args := c.InsertArgs(object, "#all", nil)
+		if len(fields) != 7 || len(args) != 7 {
+			t.Error(fields, args)
+			return
+		}
+		if *args[2].(*int64) != now.UnixNano()/1e6 || !time.Time(*args[4].(*xsql.Time)).Equal(now) || args[5].(*xsql.Time).Timestamp() != 1000 {
+			t.Error(args)
+			return
+		}
+		if object.Int64Value != 0 || object.UpdateTime.Timestamp() > 0 || object.CreateTime.Timestamp() != 1000 {
+			t.Error(object)
+			return
+		}
+		created := time.Unix(100, 0)
+		pointer := &struct {
+			T          string     `json:"-" table:"crud_object"`
+			CreateTime *time.Time `json:"create_time" autotime:"create"`
+			UpdateTime *time.Time `json:"update_time" autotime:"update"`
+		}{UpdateTime: &created}
+		_, _, _, args = c.InsertArgs(pointer, "#all", nil)
+		if pointer.CreateTime != nil || !(*args[0].(**time.Time)).Equal(now) || !created.Equal(time.Unix(100, 0)) {
+			t.Error(pointer.CreateTime, args)
+			return
+		}
+		_, _, args = c.UpdateArgs(pointer, "update_time", nil)
+		if !(*args[0].(**time.Time)).Equal(now) || !created.Equal(time.Unix(100, 0)) {
+			t.Error(args)
+			return
+		}
+		sql, _ := c.InsertSQL(object, "^tid")
+		if sql != "insert into crud_object(title,int64_value,time_value,update_time,create_time) values($1,$2,$3,$4,$5) " {
+			t.Error(sql)
+			return
+		}
+		_, err := c.InsertFilter(&eventQueryer{}, context.Background(), object, "^tid", "", "")
+		if err != nil || object.Int64Value != now.UnixNano()/1e6 || !time.Time(object.UpdateTime).Equal(now) || object.CreateTime.Timestamp() != 1000 {
+			t.Error(err, object)
+			return
+		}
+	}
+	{ //update
+		object := &AutoTimeCrudObject{TID: 1, Title: "abc", UpdateTime: xsql.TimeUnix(1000)}
+		_, sets, args := c.UpdateArgs(object, "title", nil)
+		if len(sets) != 2 || sets[0] != "title=$1" || sets[1] != "update_time=$2" || len(args) != 2 || !time.Time(*args[1].(*xsql.Time)).Equal(now) || object.UpdateTime.Timestamp() != 1000 {
+			t.Error(sets, args, object)
+			return
+		}
+		_, sets, _ = c.UpdateArgs(object, "update_time,title", nil)
+		if len(sets) != 2 {
+			t.Error(sets)
+			return
+		}
+		sql, _ := c.UpsertSQL(object, "tid,title", "tid", "title")
+		if sql != "insert into crud_object(tid,title) values($1,$2) on conflict (tid) do update set title=excluded.title,update_time=excluded.update_time" || object.UpdateTime.Timestamp() != 1000 {
+			t.Error(sql, object)
+			return
+		}
+		_, err := c.UpdateWheref(&eventQueryer{}, context.Background(), object, "title", "tid=$%v", 1)
+		if err != nil || !time.Time(object.UpdateTime).Equal(now) {
+			t.Error(err, object)
+			return
+		}
+		object.UpdateTime = xsql.TimeUnix(1000)
+		_, err = c.UpsertFilter(&eventQueryer{}, context.Background(), object, "tid,title", "tid", "title", "")
+		if err != nil || !time.Time(object.UpdateTime).Equal(now) {
+			t.Error(err, object)
+			return
+		}
+		_, sets, _ = c.UpdateArgs(&CrudObject{Title: "abc"}, "title", nil)
+		if len(sets) != 1 {
+			t.Error(sets)
+			return
+		}
+	}
+	{ //default clock
+		object := &AutoTimeCrudObject{Title: "abc"}
+		_, _, _, args := InsertArgs(object, "#all", nil)
+		if args[5].(*xsql.Time).Timestamp() < 1 || *args[2].(*int64) < 1 {
+			t.Error(args)
+			return
+		}
+	}
+}
+
+func TestAutoTimeQuery(t *testing.T) {
+	clearPG()
+	testAutoTimeQuery(t, getPG())
+}
+
+func testAutoTimeQuery(t *testing.T, queryer Queryer) {
+	ctx := context.Background()
+	object := &AutoTimeCrudObject{Title: "abc", Status: CrudObjectStatusNormal}
+	_, err := InsertFilter(queryer, ctx, object, "^tid#all", "returning", "tid#all")
+	if err != nil || object.TID < 1 || object.CreateTime.Timestamp() < 1 {
+		t.Error(err, object)
+		return
+	}
+	object.UpdateTime = xsql.TimeUnix(1000)
+	object.Title = "updated"
+	err = UpdateRowWheref(queryer, ctx, object, "title", "tid=$%v", object.TID)
+	if err != nil || object.UpdateTime.Timestamp() <= 1000 {
+		t.Error(err, object)
+		return
+	}
+	found, err := NewRepo[AutoTimeCrudObject](nil).Find(ctx, queryer, "tid=$%v", object.TID)
+	if err != nil || found.Title != "updated" || found.UpdateTime.Timestamp() <= 1000 {
+		t.Error(err, found)
+		return
+	}
+}
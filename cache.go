@@ -0,0 +1,166 @@
+package crud
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// fieldMeta is the resolved field info of struct by filter
+type fieldMeta struct {
+	Index   []int
+	Name    string
+	Func    string
+	Field   reflect.StructField
+	IncNil  bool
+	IncZero bool
+	Cmp     string
+	Conv    string
+	Join    string
+}
+
+type filterMeta struct {
+	Fields []*fieldMeta
+}
+
+type filterKey struct {
+	Type   reflect.Type
+	On     string
+	Filter string
+}
+
+// FilterCacheMax is the max count of cached filterMeta on one CRUD, the filter is resolved without caching when exceeded
+var FilterCacheMax int64 = 4096
+
+// filterCache is the cache of filterMeta by (type, on, filter) on one CRUD, it is dropped when the CRUD is copied or the Tag/NameConv is changed
+type filterCache struct {
+	owner    *CRUD
+	tag      string
+	nameConv uintptr
+	metas    sync.Map
+	size     int64
+}
+
+// ResetCache will drop the cached filterMeta, it should be called after replacing NameConv by the closure of same func literal
+func (c *CRUD) ResetCache() {
+	c.cache.Store(&filterCache{owner: c, tag: c.Tag, nameConv: reflect.ValueOf(c.NameConv).Pointer()})
+}
+
+func (c *CRUD) loadFilterCache() (cache *filterCache) {
+	cache, _ = c.cache.Load().(*filterCache)
+	if cache == nil || cache.owner != c || cache.tag != c.Tag || cache.nameConv != reflect.ValueOf(c.NameConv).Pointer() {
+		c.ResetCache()
+		cache = c.cache.Load().(*filterCache)
+	}
+	return
+}
+
+func (c *CRUD) loadFilterMeta(on string, reflectType reflect.Type, filter string) (meta *filterMeta) {
+	cache := c.loadFilterCache()
+	key := filterKey{
+		Type:   reflectType,
+		On:     on,
+		Filter: filter,
+	}
+	if cached, ok := cache.metas.Load(key); ok {
+		meta = cached.(*filterMeta)
+		return
+	}
+	meta = &filterMeta{}
+	c.parseFilterMeta(meta, on, reflectType, nil, filter)
+	if atomic.LoadInt64(&cache.size) >= FilterCacheMax {
+		return
+	}
+	cached, loaded := cache.metas.LoadOrStore(key, meta)
+	if !loaded {
+		atomic.AddInt64(&cache.size, 1)
+	}
+	meta = cached.(*filterMeta)
+	return
+}
+
+// parseFilterMeta is the same as attrscan.Scanner.FilterFieldCall, but only resolve the field info without value
+func (c *CRUD) parseFilterMeta(meta *filterMeta, on string, reflectType reflect.Type, index []int, filter string) {
+	for _, f := range strings.Split(filter, "|") {
+		c.parseFilterOnceMeta(meta, on, reflectType, index, f)
+	}
+}
+
+func (c *CRUD) parseFilterOnceMeta(meta *filterMeta, on string, reflectType reflect.Type, index []int, filter string) {
+	var fieldAll = map[string]string{}
+	var isExc = false
+	var incNil, incZero bool
+	var alias string
+	if len(filter) > 0 {
+		filter = strings.TrimSpace(filter)
+		parts := strings.SplitN(filter, ".", 2)
+		if len(parts) > 1 {
+			alias = parts[0] + "."
+			filter = parts[1]
+		}
+		parts = strings.SplitN(filter, "#", 2)
+		isExc = strings.HasPrefix(parts[0], "^")
+		if len(parts[0]) > 0 {
+			for _, fieldItem := range strings.Split(strings.TrimPrefix(parts[0], "^"), ",") {
+				fieldParts := strings.SplitN(strings.Trim(strings.TrimSpace(fieldItem), ")"), "(", 2)
+				if len(fieldParts) > 1 {
+					fieldAll[fieldParts[1]] = fieldParts[0]
+				} else {
+					fieldAll[fieldParts[0]] = ""
+				}
+			}
+		}
+		if len(parts) > 1 && len(parts[1]) > 0 {
+			incNil = strings.Contains(","+parts[1]+",", ",nil,") || strings.Contains(","+parts[1]+",", ",all,")
+			incZero = strings.Contains(","+parts[1]+",", ",zero,") || strings.Contains(","+parts[1]+",", ",all,")
+		}
+	}
+	numField := reflectType.NumField()
+	for i := 0; i < numField; i++ {
+		fieldType := reflectType.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+		fieldName := strings.SplitN(fieldType.Tag.Get(c.Tag), ",", 2)[0]
+		fieldFilter := strings.TrimSpace(strings.TrimPrefix(fieldType.Tag.Get("filter"), "#"))
+		fieldIncNil, fieldIncZero, fieldInline := incNil, incZero, false
+		if len(fieldFilter) > 0 {
+			fieldIncNil = strings.Contains(","+fieldFilter+",", ",nil,") || strings.Contains(","+fieldFilter+",", ",all,")
+			fieldIncZero = strings.Contains(","+fieldFilter+",", ",zero,") || strings.Contains(","+fieldFilter+",", ",all,")
+			fieldInline = strings.Contains(","+fieldFilter+",", ",inline,")
+		}
+		if fieldInline {
+			c.parseFilterMeta(meta, on, fieldType.Type, fieldIndex, filter)
+			continue
+		}
+		if len(fieldName) < 1 || fieldName == "-" {
+			continue
+		}
+		if _, ok := fieldAll[fieldName]; (isExc && ok) || (!isExc && len(fieldAll) > 0 && !ok) {
+			continue
+		}
+		fieldName = c.NameConv(on, fieldName, fieldType)
+		meta.Fields = append(meta.Fields, &fieldMeta{
+			Index:   fieldIndex,
+			Name:    alias + fieldName,
+			Func:    fieldAll[fieldName],
+			Field:   fieldType,
+			IncNil:  fieldIncNil,
+			IncZero: fieldIncZero,
+			Cmp:     fieldType.Tag.Get("cmp"),
+			Conv:    fieldType.Tag.Get("conv"),
+			Join:    fieldType.Tag.Get("join"),
+		})
+	}
+}
+
+func (c *CRUD) filterStructCall(on string, v interface{}, filter string, call func(field *fieldMeta, value interface{})) {
+	reflectValue := reflect.Indirect(reflect.ValueOf(v))
+	meta := c.loadFilterMeta(on, reflectValue.Type(), filter)
+	for _, field := range meta.Fields {
+		fieldValue := reflectValue.FieldByIndex(field.Index)
+		if !c.Scanner.CheckValue(fieldValue, field.IncNil, field.IncZero) {
+			continue
+		}
+		call(field, fieldValue.Addr().Interface())
+	}
+}
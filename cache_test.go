@@ -0,0 +1,196 @@
+package crud
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/codingeasygo/util/attrscan"
+	"github.com/codingeasygo/util/converter"
+	"github.com/codingeasygo/util/xsql"
+)
+
+type CacheInlineObject struct {
+	Level int    `json:"level"`
+	Title string `json:"title" filter:"#all"`
+}
+
+type CacheObject struct {
+	T      string            `table:"crud_object"`
+	TID    int64             `json:"tid" cmp:"tid>$%v"`
+	Type   string            `json:"type"`
+	Inline CacheInlineObject `filter:"inline"`
+	Data   xsql.M            `json:"data" conv:"::jsonb"`
+	Image  *string           `json:"image"`
+	Ignore string            `json:"-"`
+	Status int               `json:"status"`
+}
+
+type cacheCall struct {
+	Name  string
+	Func  string
+	Field string
+	Value interface{}
+}
+
+func TestFilterCache(t *testing.T) {
+	object := &CacheObject{TID: 100, Type: "a", Data: xsql.M{"a": 1}, Inline: CacheInlineObject{Level: 1}}
+	filters := []string{
+		"", "#all", "#nil", "#zero", "tid,type", "tid,type#all", "^tid,type", "^tid#all",
+		"o.tid,type#all", "count(tid),max(status)#all", "tid#all|data#all", "tid|^tid#all", " o.tid , type ",
+	}
+	for _, on := range []string{"insert", "query", "where"} {
+		for _, filter := range filters {
+			var cached, raw []cacheCall
+			Default.filterStructCall(on, object, filter, func(field *fieldMeta, value interface{}) {
+				cached = append(cached, cacheCall{Name: field.Name, Func: field.Func, Field: field.Field.Name, Value: value})
+			})
+			Default.Scanner.FilterFieldCall(on, object, filter, func(fieldName, fieldFunc string, field reflect.StructField, value interface{}) {
+				raw = append(raw, cacheCall{Name: fieldName, Func: fieldFunc, Field: field.Name, Value: value})
+			})
+			if !reflect.DeepEqual(cached, raw) {
+				t.Errorf("on:%v,filter:%v\ncached:%v\nraw:%v", on, filter, converter.JSON(cached), converter.JSON(raw))
+				return
+			}
+		}
+	}
+	{ //cached value is pointer to field
+		var title string
+		Default.FilterFieldCall("query", object, "title#all", func(fieldName, fieldFunc string, field reflect.StructField, value interface{}) {
+			*(value.(*string)) = "cached"
+			title = object.Inline.Title
+		})
+		if title != "cached" {
+			t.Error(title)
+			return
+		}
+	}
+	{ //cached tags
+		where, args := Default.FilterWhere(nil, object, "tid,type")
+		if len(where) != 2 || where[0] != "tid>$1" || where[1] != "type = $2" || len(args) != 2 {
+			t.Error(where, args)
+			return
+		}
+		_, fields := QueryField(object, "data#all")
+		if len(fields) != 1 || fields[0] != "data::text::jsonb" {
+			t.Error(fields)
+			return
+		}
+	}
+	{ //name conv change
+		crud := NewCRUD(DialectPostgres)
+		_, fields := crud.QueryField(object, "tid,type")
+		if len(fields) != 2 || fields[0] != "tid" {
+			t.Error(fields)
+			return
+		}
+		crud.NameConv = func(on, name string, field reflect.StructField) string { return "x_" + name }
+		_, fields = crud.QueryField(object, "tid,type")
+		if len(fields) != 2 || fields[0] != "x_tid" {
+			t.Error(fields)
+			return
+		}
+	}
+	{ //same func literal
+		prefix := func(p string) attrscan.NameConv {
+			return func(on, name string, field reflect.StructField) string { return p + name }
+		}
+		crud := NewCRUD(DialectPostgres)
+		crud.NameConv = prefix("a_")
+		_, fields := crud.QueryField(object, "tid")
+		copied := *crud
+		copied.NameConv = prefix("b_")
+		_, copiedFields := copied.QueryField(object, "tid")
+		if fields[0] != "a_tid" || copiedFields[0] != "b_tid" {
+			t.Error(fields, copiedFields)
+			return
+		}
+		crud.NameConv = prefix("c_")
+		crud.ResetCache()
+		_, fields = crud.QueryField(object, "tid")
+		if fields[0] != "c_tid" {
+			t.Error(fields)
+			return
+		}
+	}
+	{ //max
+		crud := NewCRUD(DialectPostgres)
+		max := FilterCacheMax
+		FilterCacheMax = 2
+		for i := 0; i < 10; i++ {
+			crud.QueryField(object, fmt.Sprintf("tid,type#%v", i))
+		}
+		FilterCacheMax = max
+		if size := crud.loadFilterCache().size; size != 2 {
+			t.Error(size)
+			return
+		}
+		if _, fields := crud.QueryField(object, "tid,type#9"); len(fields) != 2 {
+			t.Error(fields)
+			return
+		}
+	}
+	{ //concurrent
+		waiter := sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			waiter.Add(1)
+			go func(i int) {
+				defer waiter.Done()
+				for j := 0; j < 100; j++ {
+					ScanArgs(&CacheObject{}, fmt.Sprintf("tid,type,status#%v", j%3))
+				}
+			}(i)
+		}
+		waiter.Wait()
+	}
+}
+
+func BenchmarkFilterFieldCall(b *testing.B) {
+	object := newTestObject()
+	call := func(fieldName, fieldFunc string, field reflect.StructField, value interface{}) {}
+	b.Run("Raw", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Default.Scanner.FilterFieldCall("insert", object, "^tid#all", call)
+		}
+	})
+	b.Run("Cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Default.FilterFieldCall("insert", object, "^tid#all", call)
+		}
+	})
+}
+
+func BenchmarkScanArgs(b *testing.B) {
+	object := newTestObject()
+	rawScanArgs := func(v interface{}, filter string) (args []interface{}) {
+		Default.Scanner.FilterFieldCall("scan", v, filter, func(fieldName, fieldFunc string, field reflect.StructField, value interface{}) {
+			args = append(args, Default.ParmConv("scan", fieldName, fieldFunc, field, value))
+		})
+		return
+	}
+	b.Run("Raw", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rawScanArgs(object, "#all")
+		}
+	})
+	b.Run("Cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ScanArgs(object, "#all")
+		}
+	})
+}
+
+func BenchmarkInsertArgs(b *testing.B) {
+	object := newTestObject()
+	for i := 0; i < b.N; i++ {
+		InsertArgs(object, "^tid#all", nil)
+	}
+}
+
+func BenchmarkFilterWhere(b *testing.B) {
+	object := newTestObject()
+	for i := 0; i < b.N; i++ {
+		Default.FilterWhere(nil, object, "type,title,status")
+	}
+}
@@ -4,10 +4,15 @@ package crud
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/codingeasygo/util/attrscan"
 	"github.com/codingeasygo/util/xsql"
@@ -61,6 +66,37 @@ func BuildOrderby(supported string, order string) (orderby string) {
 	return
 }
 
+// ParseOrderby will parse client order like "-update_time,+tid" to orderby by supported keys,
+// the key without +/- prefix is not having direction, error is returned when key is not supported
+func ParseOrderby(supported string, order string) (orderby string, err error) {
+	orderby, err = parseOrderby(supported, order, func(key string) string { return key })
+	return
+}
+
+// parseOrderby will parse client order by raw supported keys, the key is quoted by quote when emitting
+func parseOrderby(supported string, order string, quote func(key string) string) (orderby string, err error) {
+	keys := []string{}
+	for _, key := range strings.Split(order, ",") {
+		key = strings.TrimSpace(key)
+		direction := ""
+		if strings.HasPrefix(key, "+") {
+			direction = " asc"
+		} else if strings.HasPrefix(key, "-") {
+			direction = " desc"
+		}
+		if len(direction) > 0 {
+			key = strings.TrimSpace(key[1:])
+		}
+		if len(key) < 1 || !xsql.AsStringArray(supported).HavingOne(key) {
+			err = fmt.Errorf("order %v is not supported by %v", order, supported)
+			return
+		}
+		keys = append(keys, quote(key)+direction)
+	}
+	orderby = "order by " + strings.Join(keys, ",")
+	return
+}
+
 func NewValue(v interface{}) (value reflect.Value) {
 	if v, ok := v.([]interface{}); ok {
 		result := []interface{}{}
@@ -105,6 +141,7 @@ var Default = &CRUD{
 		},
 	},
 	ArgFormat: "$%v",
+	Dialect:   DialectPostgres,
 	ErrNoRows: nil,
 	Log: func(caller int, format string, args ...interface{}) {
 		log.Output(caller+3, fmt.Sprintf(format, args...))
@@ -124,14 +161,49 @@ func Args(args ...interface{}) []interface{} {
 	return args
 }
 
+// DefaultArgLimit is the max bind parameter count of one statement when CRUD.ArgLimit is not setted,
+// it is safe for postgres(65535) and sqlite(32766)
+const DefaultArgLimit = 32766
+
 type CRUD struct {
 	attrscan.Scanner
-	ArgFormat   string
-	ErrNoRows   error
-	Verbose     bool
-	Log         LogF
-	TablePrefix string
-	ParmConv    ParmConv
+	ArgFormat     string
+	Dialect       Dialect
+	ArgLimit      int
+	QuoteIdent    bool
+	ErrNoRows     error
+	Verbose       bool
+	Log           LogF
+	TablePrefix   string
+	ParmConv      ParmConv
+	Interceptors  []Interceptor
+	Logger        Logger
+	LogLevel      LogLevel
+	SlowThreshold time.Duration
+	RedactColumns []string
+	CursorKey     []byte
+	MaxLimit      int
+	MaxOffset     int
+	Strict        bool
+	Now           func() time.Time
+	AuditTable    string
+	cache         atomic.Value
+}
+
+func NewCRUD(dialect Dialect) (c *CRUD) {
+	c = &CRUD{
+		Scanner: attrscan.Scanner{
+			Tag: "json",
+			NameConv: func(on, name string, field reflect.StructField) string {
+				return name
+			},
+		},
+		ArgFormat: "$%v",
+		Dialect:   dialect,
+		Log:       Default.Log,
+		ParmConv:  dialect.ParmConv,
+	}
+	return
 }
 
 func (c *CRUD) getErrNoRows() (err error) {
@@ -143,11 +215,41 @@ func (c *CRUD) getErrNoRows() (err error) {
 	return
 }
 
+// isNoRows will return if err is the no rows error of CRUD.ErrNoRows or ErrNoRows
+func (c *CRUD) isNoRows(err error) bool {
+	return err != nil && (errors.Is(err, c.getErrNoRows()) || errors.Is(err, ErrNoRows))
+}
+
+func (c *CRUD) argLimit() (limit int) {
+	limit = c.ArgLimit
+	if limit < 1 {
+		limit = DefaultArgLimit
+	}
+	return
+}
+
 func Table(v interface{}) (table string) {
 	table = Default.Table(v)
 	return
 }
 
+// modelTag will return the tag value of key on T or _ field of model struct
+func (c *CRUD) modelTag(v interface{}, key string) (tag string) {
+	reflectValue := reflect.Indirect(reflect.ValueOf(v))
+	if reflectValue.Kind() != reflect.Struct {
+		return
+	}
+	reflectType := reflectValue.Type()
+	for _, name := range []string{"T", "_"} {
+		if fieldType, ok := reflectType.FieldByName(name); ok {
+			if tag = fieldType.Tag.Get(key); len(tag) > 0 {
+				return
+			}
+		}
+	}
+	return
+}
+
 func (c *CRUD) Table(v interface{}) (table string) {
 	if v, ok := v.([]interface{}); ok {
 		for _, f := range v {
@@ -159,6 +261,7 @@ func (c *CRUD) Table(v interface{}) (table string) {
 				break
 			}
 		}
+		table = c.quoteTable(table)
 		return
 	}
 	reflectValue := reflect.Indirect(reflect.ValueOf(v))
@@ -186,6 +289,113 @@ func (c *CRUD) Table(v interface{}) (table string) {
 			}
 		}
 	}
+	table = c.quoteTable(table)
+	return
+}
+
+func (c *CRUD) quoteTable(table string) string {
+	if !c.QuoteIdent || c.Dialect == nil || len(table) < 1 || strings.ContainsAny(table, "(\"`") {
+		return table
+	}
+	parts := strings.SplitN(table, " ", 2)
+	names := strings.Split(parts[0], ".")
+	for i, name := range names {
+		names[i] = c.Dialect.Quote(name)
+	}
+	parts[0] = strings.Join(names, ".")
+	return strings.Join(parts, " ")
+}
+
+// quote will quote the field name by dialect when QuoteIdent is enabled,
+// it will keep the table alias(o.type), the conv cast(::text) and function call/expression
+func (c *CRUD) quote(name string) string {
+	if !c.QuoteIdent || c.Dialect == nil || len(name) < 1 || strings.ContainsAny(name, "( \"`") {
+		return name
+	}
+	var alias, cast string
+	if i := strings.Index(name, "::"); i > 0 {
+		name, cast = name[:i], name[i:]
+	}
+	if i := strings.LastIndex(name, "."); i > 0 {
+		alias, name = name[:i+1], name[i+1:]
+	}
+	if name == "*" {
+		return alias + name + cast
+	}
+	return alias + c.Dialect.Quote(name) + cast
+}
+
+func (c *CRUD) placeholder(v int) string {
+	if c.Dialect == nil {
+		return fmt.Sprintf(c.ArgFormat, v)
+	}
+	return c.Dialect.Placeholder(v)
+}
+
+// placeholderAll will return the position and arg index of all placeholder in sql,
+// the positional placeholder like ? is matched to the tail of args which having n items
+func (c *CRUD) placeholderAll(sql string, n int) (positions, indexes []int) {
+	if c.placeholder(1) == c.placeholder(2) {
+		placeholder := c.placeholder(1)
+		for i := strings.Index(sql, placeholder); i >= 0; {
+			positions = append(positions, i)
+			next := strings.Index(sql[i+len(placeholder):], placeholder)
+			if next < 0 {
+				break
+			}
+			i += len(placeholder) + next
+		}
+		for i := range positions {
+			indexes = append(indexes, n-len(positions)+i)
+		}
+		return
+	}
+	parts := strings.SplitN(c.placeholder(987654321), "987654321", 2)
+	placeholder := regexp.MustCompile(regexp.QuoteMeta(parts[0]) + `(\d+)` + regexp.QuoteMeta(parts[1]))
+	for _, match := range placeholder.FindAllStringSubmatchIndex(sql, -1) {
+		index, _ := strconv.Atoi(sql[match[2]:match[3]])
+		positions = append(positions, match[0])
+		indexes = append(indexes, index-1)
+	}
+	return
+}
+
+func (c *CRUD) returning() bool {
+	return c.Dialect == nil || c.Dialect.Returning()
+}
+
+func (c *CRUD) scanInsertId(v interface{}, scan string, insertId int64) (err error) {
+	scanArgs := c.ScanArgs(v, scan)
+	if len(scanArgs) != 1 {
+		err = fmt.Errorf("scan %v must be only one field when returning is not supported", scan)
+		return
+	}
+	target := reflect.Indirect(reflect.ValueOf(scanArgs[0]))
+	if target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		target = target.Elem()
+	}
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		target.SetInt(insertId)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		target.SetUint(uint64(insertId))
+	default:
+		err = fmt.Errorf("scan %v type %v is not supported to set insert id", scan, target.Type())
+	}
+	return
+}
+
+// appendArg will append arg to args, the arg is appended once for each placeholder in format when the placeholder of dialect is positional like ?
+func (c *CRUD) appendArg(args []interface{}, format string, arg interface{}) (args_ []interface{}) {
+	args_ = append(args, arg)
+	if c.placeholder(1) == c.placeholder(2) {
+		for i := strings.Count(format, c.ArgFormat); i > 1; i-- {
+			args_ = append(args_, arg)
+		}
+	}
 	return
 }
 
@@ -193,24 +403,48 @@ func (c *CRUD) Sprintf(format string, v int) string {
 	args := []interface{}{}
 	arg := fmt.Sprintf("%d", v)
 	n := strings.Count(format, c.ArgFormat)
+	if c.Dialect != nil {
+		format = strings.ReplaceAll(format, c.ArgFormat, "%v")
+		arg = c.Dialect.Placeholder(v)
+	}
 	for i := 0; i < n; i++ {
 		args = append(args, arg)
 	}
 	return fmt.Sprintf(format, args...)
 }
 
-func FilterFieldCall(on string, v interface{}, filter string, call func(fieldName, fieldFunc string, field reflect.StructField, value interface{})) (table string) {
-	table = Default.FilterFieldCall(on, v, filter, call)
+func FilterFieldCall(on string, v interface{}, filter interface{}, call func(fieldName, fieldFunc string, field reflect.StructField, value interface{})) (table string) {
+	table = Default.FilterFieldCall(on, v, filterString(filter), call)
+	return
+}
+
+func (c *CRUD) FilterFieldCall(on string, v interface{}, filter interface{}, call func(fieldName, fieldFunc string, field reflect.StructField, value interface{})) (table string) {
+	table = c.filterFieldCall(on, v, filterString(filter), func(field *fieldMeta, value interface{}) {
+		call(field.Name, field.Func, field.Field, value)
+	})
+	return
+}
+
+// FilterFieldCallErr will return ErrFilterMismatch instead of panic when filter is not matched to v
+func FilterFieldCallErr(on string, v interface{}, filter interface{}, call func(fieldName, fieldFunc string, field reflect.StructField, value interface{})) (table string, err error) {
+	table, err = Default.FilterFieldCallErr(on, v, filter, call)
+	return
+}
+
+// FilterFieldCallErr will return ErrFilterMismatch instead of panic when filter is not matched to v
+func (c *CRUD) FilterFieldCallErr(on string, v interface{}, filter interface{}, call func(fieldName, fieldFunc string, field reflect.StructField, value interface{})) (table string, err error) {
+	defer c.recoverError(&err)
+	table = c.FilterFieldCall(on, v, filter, call)
 	return
 }
 
-func (c *CRUD) FilterFieldCall(on string, v interface{}, filter string, call func(fieldName, fieldFunc string, field reflect.StructField, value interface{})) (table string) {
+func (c *CRUD) filterFieldCall(on string, v interface{}, filter string, call func(field *fieldMeta, value interface{})) (table string) {
 	filters := strings.Split(filter, "|")
 	called := map[string]bool{}
-	recordCall := func(fieldName, fieldFunc string, field reflect.StructField, value interface{}) {
-		if !called[fieldName] {
-			called[fieldName] = true
-			call(fieldName, fieldFunc, field, value)
+	recordCall := func(field *fieldMeta, value interface{}) {
+		if !called[field.Name] {
+			called[field.Name] = true
+			call(field, value)
 		}
 	}
 	table = c.filterFieldOnceCall(on, v, filters[0], recordCall)
@@ -220,7 +454,7 @@ func (c *CRUD) FilterFieldCall(on string, v interface{}, filter string, call fun
 	return
 }
 
-func (c *CRUD) filterFieldOnceCall(on string, v interface{}, filter string, call func(fieldName, fieldFunc string, field reflect.StructField, value interface{})) (table string) {
+func (c *CRUD) filterFieldOnceCall(on string, v interface{}, filter string, call func(field *fieldMeta, value interface{})) (table string) {
 	filter = strings.TrimSpace(filter)
 	filter = strings.TrimPrefix(filter, "*") //* equal empty
 	reflectValue := reflect.Indirect(reflect.ValueOf(v))
@@ -237,14 +471,20 @@ func (c *CRUD) filterFieldOnceCall(on string, v interface{}, filter string, call
 		offset := 0
 		for _, f := range v {
 			if tableName, ok := f.(TableName); ok {
-				table = string(tableName)
+				table = c.quoteTable(string(tableName))
 				if len(tableAlias) > 0 {
 					table = table + " " + tableAlias
 				}
 				continue
 			}
 			if offset >= len(filterFields) {
-				panic(fmt.Sprintf("meta v[%v] is not found on filter", offset))
+				values := 0
+				for _, f := range v {
+					if _, ok := f.(TableName); !ok {
+						values++
+					}
+				}
+				panic(&ErrFilterMismatch{Filter: filter, Fields: len(filterFields), Values: values})
 			}
 			fieldParts := strings.SplitN(strings.Trim(filterFields[offset], ")"), "(", 2)
 			fieldName := fieldParts[0]
@@ -253,7 +493,7 @@ func (c *CRUD) filterFieldOnceCall(on string, v interface{}, filter string, call
 				fieldName = fieldParts[1]
 				fieldFunc = fieldParts[0]
 			}
-			call(fieldAlias+fieldName, fieldFunc, reflect.StructField{}, f)
+			call(&fieldMeta{Name: fieldAlias + fieldName, Func: fieldFunc}, f)
 			offset++
 		}
 		return
@@ -272,7 +512,7 @@ func (c *CRUD) filterFieldOnceCall(on string, v interface{}, filter string, call
 			fieldName = fieldParts[1]
 			fieldFunc = fieldParts[0]
 		}
-		call(fieldAlias+fieldName, fieldFunc, reflect.StructField{}, v)
+		call(&fieldMeta{Name: fieldAlias + fieldName, Func: fieldFunc}, v)
 		return
 	}
 	table = c.Table(v)
@@ -280,7 +520,7 @@ func (c *CRUD) filterFieldOnceCall(on string, v interface{}, filter string, call
 	if len(parts) > 1 {
 		table = table + " " + parts[0]
 	}
-	c.Scanner.FilterFieldCall(on, v, filter, call)
+	c.filterStructCall(on, v, filter, call)
 	return
 }
 
@@ -288,6 +528,19 @@ func FilterFormatCall(formats string, args []interface{}, call func(format strin
 	Default.FilterFormatCall(formats, args, call)
 }
 
+// FilterFormatCallErr will return ErrFilterMismatch instead of panic when formats is not matched to args
+func FilterFormatCallErr(formats string, args []interface{}, call func(format string, arg interface{})) (err error) {
+	err = Default.FilterFormatCallErr(formats, args, call)
+	return
+}
+
+// FilterFormatCallErr will return ErrFilterMismatch instead of panic when formats is not matched to args
+func (c *CRUD) FilterFormatCallErr(formats string, args []interface{}, call func(format string, arg interface{})) (err error) {
+	defer c.recoverError(&err)
+	c.FilterFormatCall(formats, args, call)
+	return
+}
+
 func (c *CRUD) FilterFormatCall(formats string, args []interface{}, call func(format string, arg interface{})) {
 	formatParts := strings.SplitN(formats, "#", 2)
 	var incNil, incZero bool
@@ -297,7 +550,7 @@ func (c *CRUD) FilterFormatCall(formats string, args []interface{}, call func(fo
 	}
 	formatList := strings.Split(formatParts[0], ",")
 	if len(formatList) != len(args) {
-		panic(fmt.Sprintf("count formats=%v  is not equal to args=%v", len(formatList), len(args)))
+		panic(&ErrFilterMismatch{Filter: formats, Fields: len(formatList), Values: len(args)})
 	}
 	for i, format := range formatList {
 		arg := args[i]
@@ -308,22 +561,22 @@ func (c *CRUD) FilterFormatCall(formats string, args []interface{}, call func(fo
 	}
 }
 
-func (c *CRUD) FilterWhere(args []interface{}, v interface{}, filter string) (where_ []string, args_ []interface{}) {
+func (c *CRUD) FilterWhere(args []interface{}, v interface{}, filter interface{}) (where_ []string, args_ []interface{}) {
 	args_ = args
-	c.FilterFieldCall("where", v, filter, func(fieldName, fieldFunc string, field reflect.StructField, fieldValue interface{}) {
-		join := field.Tag.Get("join")
-		if field.Type.Kind() == reflect.Struct && len(join) > 0 {
+	c.filterFieldCall("where", v, filterString(filter), func(field *fieldMeta, fieldValue interface{}) {
+		join := field.Join
+		if field.Field.Type != nil && field.Field.Type.Kind() == reflect.Struct && len(join) > 0 {
 			var cmpInner []string
-			cmpInner, args_ = c.FilterWhere(args_, fieldValue, field.Tag.Get("filter"))
+			cmpInner, args_ = c.FilterWhere(args_, fieldValue, field.Field.Tag.Get("filter"))
 			where_ = append(where_, "("+strings.Join(cmpInner, " "+join+" ")+")")
 			return
 		}
-		cmp := field.Tag.Get("cmp")
+		cmp := field.Cmp
 		if cmp == "-" {
 			return
 		}
 		if len(cmp) < 1 {
-			cmp = fieldName + " = " + c.ArgFormat
+			cmp = c.quote(field.Name) + " = " + c.ArgFormat
 		}
 		if !strings.Contains(cmp, c.ArgFormat) {
 			cmp += " " + c.ArgFormat
@@ -331,7 +584,7 @@ func (c *CRUD) FilterWhere(args []interface{}, v interface{}, filter string) (wh
 		if (strings.Contains(cmp, " or ") || strings.Contains(cmp, " and ")) && !strings.HasPrefix(cmp, "(") {
 			cmp = "(" + cmp + ")"
 		}
-		args_ = append(args_, c.ParmConv("where", fieldName, fieldFunc, field, fieldValue))
+		args_ = c.appendArg(args_, cmp, c.redactArg(field.Name, field.Field, c.ParmConv("where", field.Name, field.Func, field.Field, fieldValue)))
 		where_ = append(where_, c.Sprintf(cmp, len(args_)))
 	})
 	return
@@ -345,8 +598,8 @@ func AppendInsert(fields, param []string, args []interface{}, ok bool, format st
 func (c *CRUD) AppendInsert(fields, param []string, args []interface{}, ok bool, format string, v interface{}) (fields_, param_ []string, args_ []interface{}) {
 	fields_, param_, args_ = fields, param, args
 	if ok {
-		args_ = append(args_, c.ParmConv("insert", format, "", reflect.StructField{}, v))
 		parts := strings.SplitN(format, "=", 2)
+		args_ = c.appendArg(args_, parts[1], c.redactFormat(parts[0], c.ParmConv("insert", format, "", reflect.StructField{}, v)))
 		param_ = append(param_, c.Sprintf(parts[1], len(args_)))
 		fields_ = append(fields_, parts[0])
 	}
@@ -361,8 +614,8 @@ func AppendInsertf(fields, param []string, args []interface{}, formats string, v
 func (c *CRUD) AppendInsertf(fields, param []string, args []interface{}, formats string, v ...interface{}) (fields_, param_ []string, args_ []interface{}) {
 	fields_, param_, args_ = fields, param, args
 	c.FilterFormatCall(formats, v, func(format string, arg interface{}) {
-		args_ = append(args_, c.ParmConv("insert", format, "", reflect.StructField{}, arg))
 		parts := strings.SplitN(format, "=", 2)
+		args_ = c.appendArg(args_, parts[1], c.redactFormat(parts[0], c.ParmConv("insert", format, "", reflect.StructField{}, arg)))
 		param_ = append(param_, c.Sprintf(parts[1], len(args_)))
 		fields_ = append(fields_, parts[0])
 	})
@@ -377,7 +630,7 @@ func AppendSet(sets []string, args []interface{}, ok bool, format string, v inte
 func (c *CRUD) AppendSet(sets []string, args []interface{}, ok bool, format string, v interface{}) (sets_ []string, args_ []interface{}) {
 	sets_, args_ = sets, args
 	if ok {
-		args_ = append(args_, c.ParmConv("update", format, "", reflect.StructField{}, v))
+		args_ = c.appendArg(args_, format, c.redactFormat(format, c.ParmConv("update", format, "", reflect.StructField{}, v)))
 		sets_ = append(sets_, c.Sprintf(format, len(args_)))
 	}
 	return
@@ -391,7 +644,7 @@ func AppendSetf(sets []string, args []interface{}, formats string, v ...interfac
 func (c *CRUD) AppendSetf(sets []string, args []interface{}, formats string, v ...interface{}) (sets_ []string, args_ []interface{}) {
 	sets_, args_ = sets, args
 	c.FilterFormatCall(formats, v, func(format string, arg interface{}) {
-		args_ = append(args_, c.ParmConv("update", format, "", reflect.StructField{}, arg))
+		args_ = c.appendArg(args_, format, c.redactFormat(format, c.ParmConv("update", format, "", reflect.StructField{}, arg)))
 		sets_ = append(sets_, c.Sprintf(format, len(args_)))
 	})
 	return
@@ -405,7 +658,7 @@ func AppendWhere(where []string, args []interface{}, ok bool, format string, v i
 func (c *CRUD) AppendWhere(where []string, args []interface{}, ok bool, format string, v interface{}) (where_ []string, args_ []interface{}) {
 	where_, args_ = where, args
 	if ok {
-		args_ = append(args_, c.ParmConv("where", format, "", reflect.StructField{}, v))
+		args_ = c.appendArg(args_, format, c.redactFormat(format, c.ParmConv("where", format, "", reflect.StructField{}, v)))
 		where_ = append(where_, c.Sprintf(format, len(args_)))
 	}
 	return
@@ -419,12 +672,25 @@ func AppendWheref(where []string, args []interface{}, format string, v ...interf
 func (c *CRUD) AppendWheref(where []string, args []interface{}, formats string, v ...interface{}) (where_ []string, args_ []interface{}) {
 	where_, args_ = where, args
 	c.FilterFormatCall(formats, v, func(format string, arg interface{}) {
-		args_ = append(args_, c.ParmConv("where", format, "", reflect.StructField{}, arg))
+		args_ = c.appendArg(args_, format, c.redactFormat(format, c.ParmConv("where", format, "", reflect.StructField{}, arg)))
 		where_ = append(where_, c.Sprintf(format, len(args_)))
 	})
 	return
 }
 
+// AppendWherefErr will return ErrFilterMismatch instead of panic when formats is not matched to v
+func AppendWherefErr(where []string, args []interface{}, formats string, v ...interface{}) (where_ []string, args_ []interface{}, err error) {
+	where_, args_, err = Default.AppendWherefErr(where, args, formats, v...)
+	return
+}
+
+// AppendWherefErr will return ErrFilterMismatch instead of panic when formats is not matched to v
+func (c *CRUD) AppendWherefErr(where []string, args []interface{}, formats string, v ...interface{}) (where_ []string, args_ []interface{}, err error) {
+	defer c.recoverError(&err)
+	where_, args_ = c.AppendWheref(where, args, formats, v...)
+	return
+}
+
 func AppendWhereUnify(where []string, args []interface{}, v interface{}, enabled ...string) (where_ []string, args_ []interface{}) {
 	where_, args_ = Default.AppendWhereUnify(where, args, v, enabled...)
 	return
@@ -483,13 +749,53 @@ func (c *CRUD) JoinWheref(sql string, args []interface{}, formats string, format
 	return
 }
 
+// JoinWherefErr will return ErrFilterMismatch instead of panic when formats is not matched to formatArgs
+func JoinWherefErr(sql string, args []interface{}, formats string, formatArgs ...interface{}) (sql_ string, args_ []interface{}, err error) {
+	defer Default.recoverError(&err)
+	sql_, args_, err = Default.joinWherefErr(1, sql, args, formats, formatArgs...)
+	return
+}
+
+// JoinWherefErr will return ErrFilterMismatch instead of panic when formats is not matched to formatArgs
+func (c *CRUD) JoinWherefErr(sql string, args []interface{}, formats string, formatArgs ...interface{}) (sql_ string, args_ []interface{}, err error) {
+	defer c.recoverError(&err)
+	sql_, args_, err = c.joinWherefErr(1, sql, args, formats, formatArgs...)
+	return
+}
+
+func (c *CRUD) joinWherefErr(caller int, sql string, args []interface{}, formats string, formatArgs ...interface{}) (sql_ string, args_ []interface{}, err error) {
+	sql_, args_ = c.joinWheref(caller+1, sql, args, formats, formatArgs...)
+	return
+}
+
 func (c *CRUD) joinWheref(caller int, sql string, args []interface{}, formats string, formatArgs ...interface{}) (sql_ string, args_ []interface{}) {
-	sql_, args_ = sql, args
+	sql_ = sql
+	where, args_, sep := c.wherefArgs(args, formats, formatArgs...)
+	if len(formats) < 1 && len(where) < 1 {
+		return
+	}
+	sql_ = c.joinWhere(caller+1, sql, where, sep)
+	return
+}
+
+// joinWherefScope will join where by formats and the model scope of v like softdelete
+func (c *CRUD) joinWherefScope(caller int, ctx context.Context, v interface{}, sql string, args []interface{}, formats string, formatArgs ...interface{}) (sql_ string, args_ []interface{}, err error) {
+	sql_ = sql
+	where, args_, sep := c.wherefArgs(args, formats, formatArgs...)
+	where, args_, sep, err = c.scopeWhere(ctx, v, where, args_, sep)
+	if err != nil || (len(formats) < 1 && len(where) < 1) {
+		return
+	}
+	sql_ = c.joinWhere(caller+1, sql, where, sep)
+	return
+}
+
+// wherefArgs will append where by formats and return the join sep of formats option
+func (c *CRUD) wherefArgs(args []interface{}, formats string, formatArgs ...interface{}) (where []string, args_ []interface{}, sep string) {
+	args_, sep = args, "and"
 	if len(formats) < 1 {
 		return
 	}
-	var where []string
-	sep := "and"
 	formatParts := strings.SplitN(formats, "#", 2)
 	if len(formatParts) > 1 {
 		optionParts := strings.Split(formatParts[1], ",")
@@ -501,34 +807,114 @@ func (c *CRUD) joinWheref(caller int, sql string, args []interface{}, formats st
 		}
 	}
 	where, args_ = c.AppendWheref(nil, args_, formats, formatArgs...)
-	sql_ = c.joinWhere(caller+1, sql, where, sep)
 	return
 }
 
+// scopeWhere will append the predicate of model scope like softdelete/tenant/named scope to where, the where is grouped by parentheses
+func (c *CRUD) scopeWhere(ctx context.Context, v interface{}, where []string, args []interface{}, sep string) (where_ []string, args_ []interface{}, sep_ string, err error) {
+	where_, args_, sep_ = where, args, sep
+	scope := c.softWhere(ctx, v, nil)
+	scope, args_, err = c.tenantWhere(ctx, v, scope, args_)
+	if err != nil {
+		return
+	}
+	scope, args_, err = c.namedWhere(ctx, v, scope, args_)
+	if err != nil || len(scope) < 1 {
+		return
+	}
+	where_, sep_ = c.groupWhere(where, sep, scope...)
+	return
+}
+
+// updateScope is the same as scopeWhere, but the softdelete is not applied
+func (c *CRUD) updateScope(ctx context.Context, v interface{}, where []string, args []interface{}, sep string) (where_ []string, args_ []interface{}, sep_ string, err error) {
+	where_, args_, sep_ = where, args, sep
+	scope, args_, err := c.tenantWhere(ctx, v, nil, args_)
+	if err != nil {
+		return
+	}
+	scope, args_, err = c.namedWhere(ctx, v, scope, args_)
+	if err != nil || len(scope) < 1 {
+		return
+	}
+	where_, sep_ = c.groupWhere(where, sep, scope...)
+	return
+}
+
+// groupWhere will append scope to where by and, the where is always grouped by parentheses to keep the or in where from escaping the scope
+func (c *CRUD) groupWhere(where []string, sep string, scope ...string) (where_ []string, sep_ string) {
+	if len(where) > 0 {
+		where_ = []string{"(" + strings.Join(where, " "+strings.TrimSpace(sep)+" ") + ")"}
+	}
+	where_ = append(where_, scope...)
+	sep_ = "and"
+	return
+}
+
+// JoinWhereUnify will join where by unify struct without context, the tenant scope is not applied, use JoinWhereUnifyContext to apply scope in ctx and get error
 func JoinWhereUnify(sql string, args []interface{}, v interface{}, enabled ...string) (sql_ string, args_ []interface{}) {
-	sql_, args_ = Default.joinWhereUnify(1, sql, args, v, enabled...)
+	sql_, args_ = Default.JoinWhereUnify(sql, args, v, enabled...)
 	return
 }
 
+// JoinWhereUnify will join where by unify struct without context, the tenant scope is not applied, use JoinWhereUnifyContext to apply scope in ctx and get error
 func (c *CRUD) JoinWhereUnify(sql string, args []interface{}, v interface{}, enabled ...string) (sql_ string, args_ []interface{}) {
-	sql_, args_ = c.joinWhereUnify(1, sql, args, v, enabled...)
+	sql_, args_, err := c.joinWhereUnify(1, WithoutTenant(context.Background()), sql, args, v, enabled...)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// JoinWhereUnifyContext will join where by unify struct and the model scope in ctx like tenant
+func JoinWhereUnifyContext(ctx context.Context, sql string, args []interface{}, v interface{}, enabled ...string) (sql_ string, args_ []interface{}, err error) {
+	sql_, args_, err = Default.joinWhereUnify(1, ctx, sql, args, v, enabled...)
+	return
+}
+
+// JoinWhereUnifyContext will join where by unify struct and the model scope in ctx like tenant
+func (c *CRUD) JoinWhereUnifyContext(ctx context.Context, sql string, args []interface{}, v interface{}, enabled ...string) (sql_ string, args_ []interface{}, err error) {
+	sql_, args_, err = c.joinWhereUnify(1, ctx, sql, args, v, enabled...)
 	return
 }
 
-func (c *CRUD) joinWhereUnify(caller int, sql string, args []interface{}, v interface{}, enabled ...string) (sql_ string, args_ []interface{}) {
+func (c *CRUD) joinWhereUnify(caller int, ctx context.Context, sql string, args []interface{}, v interface{}, enabled ...string) (sql_ string, args_ []interface{}, err error) {
+	where, args_, whereJoin, err := c.whereUnify(ctx, v, args, enabled...)
+	if err != nil {
+		return
+	}
+	sql_ = c.joinWhere(caller+1, sql, where, whereJoin)
+	return
+}
+
+// whereUnify will return the where by unify struct and the model scope in ctx like tenant
+func (c *CRUD) whereUnify(ctx context.Context, v interface{}, args []interface{}, enabled ...string) (where []string, args_ []interface{}, sep string, err error) {
+	where, args_ = c.AppendWhereUnify(nil, args, v)
+	where, args_, sep, err = c.scopeWhere(c.unifyScope(ctx, v, enabled...), c.unifyModel(v), where, args_, c.whereJoinUnify(v, enabled...))
+	return
+}
+
+// unifyModel will return the pointer of Model field on unify struct, nil is returned when not having Model
+func (c *CRUD) unifyModel(v interface{}) (model interface{}) {
 	reflectValue := reflect.Indirect(reflect.ValueOf(v))
-	reflectType := reflectValue.Type()
+	if reflectValue.Kind() != reflect.Struct {
+		return
+	}
+	if modelValue := reflectValue.FieldByName("Model"); modelValue.IsValid() && modelValue.CanAddr() {
+		model = modelValue.Addr().Interface()
+	}
+	return
+}
+
+func (c *CRUD) whereJoinUnify(v interface{}, enabled ...string) (whereJoin string) {
+	reflectType := reflect.Indirect(reflect.ValueOf(v)).Type()
 	if len(enabled) < 1 {
 		enabled = append(enabled, "Where")
 	}
-	whereJoin := ""
 	for _, key := range enabled {
 		whereType, _ := reflectType.FieldByName(key)
 		whereJoin += " " + whereType.Tag.Get("join")
 	}
-	args_ = args
-	where, args_ := c.AppendWhereUnify(nil, args_, v)
-	sql_ = c.joinWhere(caller+1, sql, where, whereJoin)
 	return
 }
 
@@ -547,7 +933,9 @@ func (c *CRUD) joinPage(caller int, sql, orderby string, offset, limit int) (sql
 	if len(orderby) > 0 && (offset >= 0 || limit > 0) {
 		sql_ += " " + orderby
 	}
-	if limit > 0 {
+	if limit > 0 && c.Dialect != nil {
+		sql_ += " " + c.Dialect.Page(offset, limit)
+	} else if limit > 0 {
 		sql_ += fmt.Sprintf(" limit %v offset %v", limit, offset)
 	}
 	if c.Verbose {
@@ -556,17 +944,34 @@ func (c *CRUD) joinPage(caller int, sql, orderby string, offset, limit int) (sql
 	return
 }
 
+// JoinPageUnify will join order and page by unify struct, it will panic when the order or offset is invalid, use JoinPageUnifyErr to get error
 func JoinPageUnify(sql string, v interface{}) (sql_ string) {
-	sql_ = Default.joinPageUnify(1, sql, v)
+	sql_ = Default.JoinPageUnify(sql, v)
 	return
 }
 
+// JoinPageUnify will join order and page by unify struct, it will panic when the order or offset is invalid, use JoinPageUnifyErr to get error
 func (c *CRUD) JoinPageUnify(sql string, v interface{}) (sql_ string) {
-	sql_ = c.joinPageUnify(1, sql, v)
+	sql_, err := c.joinPageUnify(1, sql, v)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// JoinPageUnifyErr will join order and page by unify struct, error is returned when the order is not supported or offset is exceeded the max
+func JoinPageUnifyErr(sql string, v interface{}) (sql_ string, err error) {
+	sql_, err = Default.joinPageUnify(1, sql, v)
+	return
+}
+
+// JoinPageUnifyErr will join order and page by unify struct, error is returned when the order is not supported or offset is exceeded the max
+func (c *CRUD) JoinPageUnifyErr(sql string, v interface{}) (sql_ string, err error) {
+	sql_, err = c.joinPageUnify(1, sql, v)
 	return
 }
 
-func (c *CRUD) joinPageUnify(caller int, sql string, v interface{}) (sql_ string) {
+func (c *CRUD) joinPageUnify(caller int, sql string, v interface{}) (sql_ string, err error) {
 	sql_ = sql
 	reflectValue := reflect.Indirect(reflect.ValueOf(v))
 	reflectType := reflectValue.Type()
@@ -580,100 +985,214 @@ func (c *CRUD) joinPageUnify(caller int, sql string, v interface{}) (sql_ string
 	orderValue := pageValue.FieldByName("Order")
 	if orderValue.IsValid() {
 		order = orderValue.String()
+		orderDefault := orderType.Tag.Get("default")
 		if len(order) < 1 {
-			order = orderType.Tag.Get("default")
+			order = orderDefault
+		} else if order != orderDefault {
+			supported := orderType.Tag.Get("orderby")
+			if len(supported) < 1 {
+				supported = c.orderbyUnify(caller+1, v)
+			}
+			order, err = parseOrderby(supported, order, c.quote)
+			if err != nil {
+				if c.Verbose {
+					c.Log(caller, "CRUD join page by struct:%v, result is fail:%v", reflect.TypeOf(v), err)
+				}
+				return
+			}
 		}
 	}
-	offset := 0
+	offset, offsetMax := 0, c.MaxOffset
+	offsetType, ok := pageType.Type.FieldByName("Offset")
+	if !ok {
+		offsetType, _ = pageType.Type.FieldByName("Skip")
+	}
 	if offsetValue := pageValue.FieldByName("Offset"); offsetValue.IsValid() {
 		offset = int(offsetValue.Int())
 	} else if skipValue := pageValue.FieldByName("Skip"); skipValue.IsValid() {
 		offset = int(skipValue.Int())
 	}
-	limit := 0
-	limitValue := pageValue.FieldByName("Limit")
-	if limitValue.IsValid() {
-		limit = int(limitValue.Int())
+	if max, _ := strconv.Atoi(offsetType.Tag.Get("max")); max > 0 {
+		offsetMax = max
+	}
+	limit := c.limitUnify(pageType, pageValue)
+	if keyset := c.keysetUnify(v); keyset != nil {
+		order = keyset.orderby()
+		if len(keyset.cursor.String()) > 0 {
+			offset = 0
+		}
+	}
+	if offsetMax > 0 && offset > offsetMax {
+		err = &PageError{Field: offsetType.Name, Value: offset, Max: offsetMax}
+		if c.Verbose {
+			c.Log(caller, "CRUD join page by struct:%v, result is fail:%v", reflect.TypeOf(v), err)
+		}
+		return
 	}
 	sql_ = c.joinPage(caller+1, sql_, order, offset, limit)
 	return
 }
 
-func (c *CRUD) queryerExec(queryer interface{}, ctx context.Context, sql string, args []interface{}) (insertId, affected int64, err error) {
+// orderbyUnify will return the supported order keys by all raw column names of Model when orderby tag is not setted on Page.Order
+func (c *CRUD) orderbyUnify(caller int, v interface{}) (supported string) {
+	model := c.unifyModel(v)
+	if model == nil {
+		return
+	}
+	columns := []string{}
+	c.filterFieldCall("query", model, "#all", func(field *fieldMeta, value interface{}) {
+		columns = append(columns, field.Name)
+	})
+	supported = strings.Join(columns, ",")
+	if c.Verbose {
+		c.Log(caller, "CRUD generate orderby by struct:%v, result is supported:%v", reflect.TypeOf(v), supported)
+	}
+	return
+}
+
+// limitUnify will return Page.Limit which is setted to default tag when not setted and clamped by max tag or CRUD.MaxLimit
+func (c *CRUD) limitUnify(pageType reflect.StructField, pageValue reflect.Value) (limit int) {
+	limitValue := pageValue.FieldByName("Limit")
+	if !limitValue.IsValid() {
+		return
+	}
+	limitType, _ := pageType.Type.FieldByName("Limit")
+	limit = int(limitValue.Int())
+	if limit <= 0 {
+		limit, _ = strconv.Atoi(limitType.Tag.Get("default"))
+	}
+	limitMax := c.MaxLimit
+	if max, _ := strconv.Atoi(limitType.Tag.Get("max")); max > 0 {
+		limitMax = max
+	}
+	if limitMax > 0 && (limit <= 0 || limit > limitMax) {
+		limit = limitMax
+	}
+	return
+}
+
+func (c *CRUD) queryerResolve(queryer interface{}, ctx context.Context) interface{} {
+	queryer = QueryerFrom(ctx, queryer)
 	reflectValue := reflect.ValueOf(queryer)
 	if reflectValue.Kind() == reflect.Func {
 		queryer = reflectValue.Call(nil)[0].Interface()
 	}
+	return queryer
+}
+
+func (c *CRUD) queryerExec(queryer interface{}, ctx context.Context, op string, v interface{}, sql string, args []interface{}) (insertId, affected int64, err error) {
+	var exec func(ctx context.Context, sql string, args ...interface{}) (insertId, affected int64, err error)
+	queryer = c.queryerResolve(queryer, ctx)
 	if q, ok := queryer.(Queryer); ok {
-		insertId, affected, err = q.Exec(ctx, sql, args...)
+		exec = q.Exec
 	} else if q, ok := queryer.(CrudQueryer); ok {
-		insertId, affected, err = q.CrudExec(ctx, sql, args...)
+		exec = q.CrudExec
 	} else {
-		panic("queryer is not supported")
+		err = c.fail(&ErrUnsupportedQueryer{Type: reflect.TypeOf(queryer)})
+		return
+	}
+	if len(c.Interceptors) < 1 && c.Logger == nil {
+		insertId, affected, err = exec(ctx, sql, c.stripArgs(args)...)
+		return
 	}
+	event := c.newEvent(op, v, sql, args)
+	err = c.intercept(ctx, event, func(ctx context.Context, event *Event) (err error) {
+		event.InsertId, event.Affected, err = exec(ctx, event.SQL, event.Args...)
+		return
+	})
+	c.logEvent(ctx, event, err)
+	insertId, affected = event.InsertId, event.Affected
 	return
 }
 
-func (c *CRUD) queryerQuery(queryer interface{}, ctx context.Context, sql string, args []interface{}) (rows Rows, err error) {
-	reflectValue := reflect.ValueOf(queryer)
-	if reflectValue.Kind() == reflect.Func {
-		queryer = reflectValue.Call(nil)[0].Interface()
-	}
+func (c *CRUD) queryerQuery(queryer interface{}, ctx context.Context, op string, v interface{}, sql string, args []interface{}) (rows Rows, err error) {
+	var query func(ctx context.Context, sql string, args ...interface{}) (rows Rows, err error)
+	queryer = c.queryerResolve(queryer, ctx)
 	if q, ok := queryer.(Queryer); ok {
-		rows, err = q.Query(ctx, sql, args...)
+		query = q.Query
 	} else if q, ok := queryer.(CrudQueryer); ok {
-		rows, err = q.CrudQuery(ctx, sql, args...)
+		query = q.CrudQuery
 	} else {
-		panic(fmt.Sprintf("queryer %v is not supported", reflect.TypeOf(queryer)))
+		err = c.fail(&ErrUnsupportedQueryer{Type: reflect.TypeOf(queryer)})
+		return
+	}
+	if len(c.Interceptors) < 1 && c.Logger == nil {
+		rows, err = query(ctx, sql, c.stripArgs(args)...)
+		return
+	}
+	begin := time.Now()
+	event := c.newEvent(op, v, sql, args)
+	err = c.intercept(ctx, event, func(ctx context.Context, event *Event) (err error) {
+		event.Rows, err = query(ctx, event.SQL, event.Args...)
+		return
+	})
+	rows = event.Rows
+	if err != nil && rows != nil {
+		rows.Close()
+		rows = nil
+	}
+	if err != nil || rows == nil || c.Logger == nil {
+		c.logEvent(ctx, event, err)
+		return
 	}
+	rows = &logRows{Rows: rows, c: c, ctx: ctx, event: event, begin: begin}
 	return
 }
 
-func (c *CRUD) queryerQueryRow(queryer interface{}, ctx context.Context, sql string, args []interface{}) (row Row) {
-	reflectValue := reflect.ValueOf(queryer)
-	if reflectValue.Kind() == reflect.Func {
-		queryer = reflectValue.Call(nil)[0].Interface()
-	}
+func (c *CRUD) queryerQueryRow(queryer interface{}, ctx context.Context, op string, v interface{}, sql string, args []interface{}) (row Row) {
+	var queryRow func(ctx context.Context, sql string, args ...interface{}) (row Row)
+	queryer = c.queryerResolve(queryer, ctx)
 	if q, ok := queryer.(Queryer); ok {
-		row = q.QueryRow(ctx, sql, args...)
+		queryRow = q.QueryRow
 	} else if q, ok := queryer.(CrudQueryer); ok {
-		row = q.CrudQueryRow(ctx, sql, args...)
+		queryRow = q.CrudQueryRow
 	} else {
-		panic(fmt.Sprintf("queryer %v is not supported", reflect.TypeOf(queryer)))
+		row = errorRow{err: c.fail(&ErrUnsupportedQueryer{Type: reflect.TypeOf(queryer)})}
+		return
+	}
+	if len(c.Interceptors) < 1 && c.Logger == nil {
+		row = queryRow(ctx, sql, c.stripArgs(args)...)
+		return
+	}
+	row = &eventRow{
+		c:     c,
+		ctx:   ctx,
+		event: c.newEvent(op, v, sql, args),
+		query: queryRow,
 	}
 	return
 }
 
-func InsertArgs(v interface{}, filter string, args []interface{}) (table string, fields, param []string, args_ []interface{}) {
-	table, fields, param, args_ = Default.insertArgs(1, v, filter, args)
+func InsertArgs(v interface{}, filter interface{}, args []interface{}) (table string, fields, param []string, args_ []interface{}) {
+	table, fields, param, args_ = Default.insertArgs(1, Default.autoTimeCopy(v, true, false), filterString(filter), args)
 	return
 }
 
-func (c *CRUD) InsertArgs(v interface{}, filter string, args []interface{}) (table string, fields, param []string, args_ []interface{}) {
-	table, fields, param, args_ = c.insertArgs(1, v, filter, args)
+func (c *CRUD) InsertArgs(v interface{}, filter interface{}, args []interface{}) (table string, fields, param []string, args_ []interface{}) {
+	table, fields, param, args_ = c.insertArgs(1, c.autoTimeCopy(v, true, false), filterString(filter), args)
 	return
 }
 
 func (c *CRUD) insertArgs(caller int, v interface{}, filter string, args []interface{}) (table string, fields, param []string, args_ []interface{}) {
 	args_ = args
 	table = c.FilterFieldCall("insert", v, filter, func(fieldName, fieldFunc string, field reflect.StructField, value interface{}) {
-		args_ = append(args_, c.ParmConv("insert", fieldName, fieldFunc, field, value))
-		fields = append(fields, fieldName)
-		param = append(param, fmt.Sprintf(c.ArgFormat, len(args_)))
+		args_ = append(args_, c.redactArg(fieldName, field, c.ParmConv("insert", fieldName, fieldFunc, field, value)))
+		fields = append(fields, c.quote(fieldName))
+		param = append(param, c.placeholder(len(args_)))
 	})
 	if c.Verbose {
-		c.Log(caller, "CRUD generate insert args by struct:%v,filter:%v, result is fields:%v,param:%v,args:%v", reflect.TypeOf(v), filter, fields, param, jsonString(args))
+		c.Log(caller, "CRUD generate insert args by struct:%v,filter:%v, result is fields:%v,param:%v,args:%v", reflect.TypeOf(v), filter, fields, param, jsonString(c.redactArgs(v, args_)))
 	}
 	return
 }
 
-func InsertSQL(v interface{}, filter string, suffix ...string) (sql string, args []interface{}) {
-	sql, args = Default.insertSQL(1, v, filter, suffix...)
+func InsertSQL(v interface{}, filter interface{}, suffix ...string) (sql string, args []interface{}) {
+	sql, args = Default.insertSQL(1, Default.autoTimeCopy(v, true, false), filterString(filter), suffix...)
 	return
 }
 
-func (c *CRUD) InsertSQL(v interface{}, filter string, suffix ...string) (sql string, args []interface{}) {
-	sql, args = c.insertSQL(1, v, filter, suffix...)
+func (c *CRUD) InsertSQL(v interface{}, filter interface{}, suffix ...string) (sql string, args []interface{}) {
+	sql, args = c.insertSQL(1, c.autoTimeCopy(v, true, false), filterString(filter), suffix...)
 	return
 }
 
@@ -686,126 +1205,454 @@ func (c *CRUD) insertSQL(caller int, v interface{}, filter string, suffix ...str
 	return
 }
 
-func InsertFilter(queryer interface{}, ctx context.Context, v interface{}, filter, join, scan string) (insertId int64, err error) {
-	insertId, err = Default.insertFilter(1, queryer, ctx, v, filter, join, scan)
+func InsertFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, join string, scan interface{}) (insertId int64, err error) {
+	defer Default.recoverError(&err)
+	insertId, err = Default.insertFilter(1, queryer, ctx, v, filterString(filter), join, filterString(scan))
 	return
 }
 
-func (c *CRUD) InsertFilter(queryer interface{}, ctx context.Context, v interface{}, filter, join, scan string) (insertId int64, err error) {
-	insertId, err = c.insertFilter(1, queryer, ctx, v, filter, join, scan)
+func (c *CRUD) InsertFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, join string, scan interface{}) (insertId int64, err error) {
+	defer c.recoverError(&err)
+	insertId, err = c.insertFilter(1, queryer, ctx, v, filterString(filter), join, filterString(scan))
 	return
 }
 
 func (c *CRUD) insertFilter(caller int, queryer interface{}, ctx context.Context, v interface{}, filter, join, scan string) (insertId int64, err error) {
+	if err = c.tenantFill(ctx, v); err != nil {
+		return
+	}
+	c.autoTimeInsert(v)
 	table, fields, param, args := c.insertArgs(caller+1, v, filter, nil)
 	sql := fmt.Sprintf(`insert into %v(%v) values(%v)`, table, strings.Join(fields, ","), strings.Join(param, ","))
 	if len(scan) < 1 {
 		if len(join) > 0 {
 			sql += " " + join
 		}
-		insertId, _, err = c.queryerExec(queryer, ctx, sql, args)
+		insertId, _, err = c.queryerExec(queryer, ctx, OpInsert, v, sql, args)
+		if err != nil {
+			if c.Verbose {
+				c.Log(caller, "CRUD insert filter by struct:%v,sql:%v, result is fail:%v", reflect.TypeOf(v), sql, err)
+			}
+		} else {
+			if c.Verbose {
+				c.Log(caller, "CRUD insert filter by struct:%v,sql:%v, result is success", reflect.TypeOf(v), sql)
+			}
+		}
+		return
+	}
+	if !c.returning() {
+		//database not support returning, so only insert id can be scanned back by LastInsertId
+		if len(join) > 0 && strings.TrimSpace(join) != "returning" {
+			sql += " " + join
+		}
+		insertId, _, err = c.queryerExec(queryer, ctx, OpInsert, v, sql, args)
+		if err == nil {
+			err = c.scanInsertId(v, scan, insertId)
+		}
+		if err != nil {
+			if c.Verbose {
+				c.Log(caller, "CRUD insert filter by struct:%v,sql:%v, result is fail:%v", reflect.TypeOf(v), sql, err)
+			}
+			return
+		}
+		if c.Verbose {
+			c.Log(caller, "CRUD insert filter by struct:%v,sql:%v, result is success", reflect.TypeOf(v), sql)
+		}
+		return
+	}
+	_, scanFields := c.queryField(caller+1, v, scan)
+	scanArgs := c.ScanArgs(v, scan)
+	if len(join) > 0 {
+		sql += " " + join
+	}
+	sql += " " + strings.Join(scanFields, ",")
+	err = c.queryerQueryRow(queryer, ctx, OpInsert, v, sql, args).Scan(scanArgs...)
+	if err != nil {
+		if c.Verbose {
+			c.Log(caller, "CRUD insert filter by struct:%v,sql:%v, result is fail:%v", reflect.TypeOf(v), sql, err)
+		}
+		return
+	}
+	if c.Verbose {
+		c.Log(caller, "CRUD insert filter by struct:%v,sql:%v, result is success", reflect.TypeOf(v), sql)
+	}
+	return
+}
+
+func InsertBatch(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, batchSize int, scan interface{}) (affected int64, err error) {
+	defer Default.recoverError(&err)
+	affected, err = Default.insertBatch(1, queryer, ctx, v, filterString(filter), batchSize, filterString(scan))
+	return
+}
+
+func (c *CRUD) InsertBatch(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, batchSize int, scan interface{}) (affected int64, err error) {
+	defer c.recoverError(&err)
+	affected, err = c.insertBatch(1, queryer, ctx, v, filterString(filter), batchSize, filterString(scan))
+	return
+}
+
+func (c *CRUD) insertBatch(caller int, queryer interface{}, ctx context.Context, v interface{}, filter string, batchSize int, scan string) (affected int64, err error) {
+	reflectValue := reflect.Indirect(reflect.ValueOf(v))
+	if reflectValue.Kind() != reflect.Slice {
+		err = fmt.Errorf("insert batch value %v is not slice", reflect.TypeOf(v))
+		return
+	}
+	if reflectValue.Len() < 1 {
+		return
+	}
+	var table string
+	var fields []string
+	items := []interface{}{}
+	values := [][]interface{}{}
+	for i := 0; i < reflectValue.Len(); i++ {
+		itemValue := reflectValue.Index(i)
+		if itemValue.Kind() != reflect.Ptr {
+			itemValue = itemValue.Addr()
+		}
+		item := itemValue.Interface()
+		if err = c.tenantFill(ctx, item); err != nil {
+			return
+		}
+		c.autoTimeInsert(item)
+		var itemFields []string
+		var itemArgs []interface{}
+		itemTable := c.FilterFieldCall("insert", item, filter, func(fieldName, fieldFunc string, field reflect.StructField, value interface{}) {
+			itemArgs = append(itemArgs, c.redactArg(fieldName, field, c.ParmConv("insert", fieldName, fieldFunc, field, value)))
+			itemFields = append(itemFields, c.quote(fieldName))
+		})
+		if i == 0 {
+			table, fields = itemTable, itemFields
+		} else if strings.Join(itemFields, ",") != strings.Join(fields, ",") {
+			err = fmt.Errorf("insert batch item[%v] fields %v is not equal to %v", i, itemFields, fields)
+			return
+		}
+		items = append(items, item)
+		values = append(values, itemArgs)
+	}
+	if len(fields) < 1 {
+		err = fmt.Errorf("insert batch fields is empty by filter %v", filter)
+		return
+	}
+	chunkSize := c.argLimit() / len(fields)
+	if batchSize > 0 && batchSize < chunkSize {
+		chunkSize = batchSize
+	}
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+	var scanFields []string
+	if len(scan) > 0 {
+		_, scanFields = c.queryField(caller+1, items[0], scan)
+	}
+	for start := 0; start < len(items); start += chunkSize {
+		end := start + chunkSize
+		if end > len(items) {
+			end = len(items)
+		}
+		var rows []string
+		var args []interface{}
+		for _, itemArgs := range values[start:end] {
+			param := []string{}
+			for _, arg := range itemArgs {
+				args = append(args, arg)
+				param = append(param, c.placeholder(len(args)))
+			}
+			rows = append(rows, "("+strings.Join(param, ",")+")")
+		}
+		sql := fmt.Sprintf(`insert into %v(%v) values%v`, table, strings.Join(fields, ","), strings.Join(rows, ","))
+		if len(scan) < 1 {
+			var chunkAffected int64
+			_, chunkAffected, err = c.queryerExec(queryer, ctx, OpInsert, v, sql, args)
+			if err != nil {
+				if c.Verbose {
+					c.Log(caller, "CRUD insert batch by struct:%v,sql:%v, result is fail:%v", reflect.TypeOf(v), sql, err)
+				}
+				return
+			}
+			affected += chunkAffected
+			continue
+		}
+		if !c.returning() {
+			var insertId, chunkAffected int64
+			insertId, chunkAffected, err = c.queryerExec(queryer, ctx, OpInsert, v, sql, args)
+			for i := 0; err == nil && i < int(chunkAffected) && start+i < end; i++ {
+				//mysql LastInsertId is the first auto increment id of multi row insert and the ids are consecutive
+				err = c.scanInsertId(items[start+i], scan, insertId+int64(i))
+			}
+			affected += chunkAffected
+			if err != nil {
+				if c.Verbose {
+					c.Log(caller, "CRUD insert batch by struct:%v,sql:%v, result is fail:%v", reflect.TypeOf(v), sql, err)
+				}
+				return
+			}
+			continue
+		}
+		sql += " returning " + strings.Join(scanFields, ",")
+		var rowsAffected int64
+		rowsAffected, err = c.insertBatchScan(queryer, ctx, sql, args, items[start:end], scan)
+		affected += rowsAffected
+		if err != nil {
+			if c.Verbose {
+				c.Log(caller, "CRUD insert batch by struct:%v,sql:%v, result is fail:%v", reflect.TypeOf(v), sql, err)
+			}
+			return
+		}
+	}
+	if c.Verbose {
+		c.Log(caller, "CRUD insert batch by struct:%v,filter:%v, result is success affected:%v", reflect.TypeOf(v), filter, affected)
+	}
+	return
+}
+
+func (c *CRUD) insertBatchScan(queryer interface{}, ctx context.Context, sql string, args []interface{}, items []interface{}, scan string) (affected int64, err error) {
+	rows, err := c.queryerQuery(queryer, ctx, OpInsert, items[0], sql, args)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if int(affected) >= len(items) {
+			err = fmt.Errorf("insert batch returning rows is more than %v", len(items))
+			break
+		}
+		err = rows.Scan(c.ScanArgs(items[affected], scan)...)
+		if err != nil {
+			break
+		}
+		affected++
+	}
+	return
+}
+
+func UpsertSQL(v interface{}, filter interface{}, conflict string, updateFilter interface{}, suffix ...string) (sql string, args []interface{}) {
+	update := filterString(updateFilter)
+	sql, args = Default.upsertSQL(1, Default.autoTimeCopy(v, true, len(update) > 0), filterString(filter), conflict, update, "", suffix...)
+	return
+}
+
+func (c *CRUD) UpsertSQL(v interface{}, filter interface{}, conflict string, updateFilter interface{}, suffix ...string) (sql string, args []interface{}) {
+	update := filterString(updateFilter)
+	sql, args = c.upsertSQL(1, c.autoTimeCopy(v, true, len(update) > 0), filterString(filter), conflict, update, "", suffix...)
+	return
+}
+
+func (c *CRUD) upsertSQL(caller int, v interface{}, filter, conflict, updateFilter, updateWhere string, suffix ...string) (sql string, args []interface{}) {
+	table, fields, param, args := c.insertArgs(caller+1, v, filter, nil)
+	var updates []string
+	if len(updateFilter) > 0 {
+		conflictFields := xsql.AsStringArray(conflict)
+		c.FilterFieldCall("update", v, updateFilter, func(fieldName, fieldFunc string, field reflect.StructField, value interface{}) {
+			if conflictFields.HavingOne(fieldName) {
+				return
+			}
+			updates = append(updates, c.quote(fieldName))
+		})
+		autoFields, _ := c.autoTimeFields(v, "update")
+		for _, field := range autoFields {
+			if !xsql.AsStringArray(updates).HavingOne(c.quote(field.Name)) {
+				updates = append(updates, c.quote(field.Name))
+			}
+		}
+	}
+	dialect := c.Dialect
+	if dialect == nil {
+		dialect = DialectPostgres
+	}
+	sql = fmt.Sprintf(`insert into %v(%v) values(%v) %v`, table, strings.Join(fields, ","), strings.Join(param, ","), dialect.Upsert(conflict, updates))
+	if len(updates) > 0 && len(updateWhere) > 0 {
+		sql += " " + updateWhere
+	}
+	if len(suffix) > 0 {
+		sql += " " + strings.Join(suffix, " ")
+	}
+	if c.Verbose {
+		c.Log(caller, "CRUD generate upsert sql by struct:%v,filter:%v,conflict:%v,update:%v, result is sql:%v", reflect.TypeOf(v), filter, conflict, updateFilter, sql)
+	}
+	return
+}
+
+// UpsertFilter will insert v or update the conflict row by updateFilter, the conflict row is skipped when updateFilter is empty,
+// nil error is returned and scan is not filled when the conflict row is skipped
+func UpsertFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, conflict string, updateFilter, scan interface{}) (insertId int64, err error) {
+	defer Default.recoverError(&err)
+	insertId, err = Default.upsertFilter(1, queryer, ctx, v, filterString(filter), conflict, filterString(updateFilter), filterString(scan))
+	return
+}
+
+// UpsertFilter will insert v or update the conflict row by updateFilter, the conflict row is skipped when updateFilter is empty,
+// nil error is returned and scan is not filled when the conflict row is skipped
+func (c *CRUD) UpsertFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, conflict string, updateFilter, scan interface{}) (insertId int64, err error) {
+	defer c.recoverError(&err)
+	insertId, err = c.upsertFilter(1, queryer, ctx, v, filterString(filter), conflict, filterString(updateFilter), filterString(scan))
+	return
+}
+
+func (c *CRUD) upsertFilter(caller int, queryer interface{}, ctx context.Context, v interface{}, filter, conflict, updateFilter, scan string) (insertId int64, err error) {
+	if err = c.tenantFill(ctx, v); err != nil {
+		return
+	}
+	updateWhere, err := c.tenantUpsert(ctx, v, updateFilter)
+	if err != nil {
+		return
+	}
+	c.autoTimeInsert(v)
+	if len(updateFilter) > 0 {
+		c.autoTimeUpdate(v)
+	}
+	sql, args := c.upsertSQL(caller+1, v, filter, conflict, updateFilter, updateWhere)
+	if len(scan) < 1 {
+		insertId, _, err = c.queryerExec(queryer, ctx, OpUpsert, v, sql, args)
 		if err != nil {
 			if c.Verbose {
-				c.Log(caller, "CRUD insert filter by struct:%v,sql:%v, result is fail:%v", reflect.TypeOf(v), sql, err)
+				c.Log(caller, "CRUD upsert filter by struct:%v,sql:%v, result is fail:%v", reflect.TypeOf(v), sql, err)
 			}
 		} else {
 			if c.Verbose {
-				c.Log(caller, "CRUD insert filter by struct:%v,sql:%v, result is success", reflect.TypeOf(v), sql)
+				c.Log(caller, "CRUD upsert filter by struct:%v,sql:%v, result is success", reflect.TypeOf(v), sql)
+			}
+		}
+		return
+	}
+	if !c.returning() {
+		insertId, _, err = c.queryerExec(queryer, ctx, OpUpsert, v, sql, args)
+		if err == nil && insertId > 0 {
+			err = c.scanInsertId(v, scan, insertId)
+		}
+		if err != nil {
+			if c.Verbose {
+				c.Log(caller, "CRUD upsert filter by struct:%v,sql:%v, result is fail:%v", reflect.TypeOf(v), sql, err)
 			}
+			return
+		}
+		if c.Verbose {
+			c.Log(caller, "CRUD upsert filter by struct:%v,sql:%v, result is success", reflect.TypeOf(v), sql)
 		}
 		return
 	}
 	_, scanFields := c.queryField(caller+1, v, scan)
 	scanArgs := c.ScanArgs(v, scan)
-	if len(join) > 0 {
-		sql += " " + join
+	sql += " returning " + strings.Join(scanFields, ",")
+	err = c.queryerQueryRow(queryer, ctx, OpUpsert, v, sql, args).Scan(scanArgs...)
+	if c.isNoRows(err) {
+		//the conflict row is skipped by do nothing or the tenant where of do update, so nothing is returned
+		err = nil
 	}
-	sql += " " + strings.Join(scanFields, ",")
-	err = c.queryerQueryRow(queryer, ctx, sql, args).Scan(scanArgs...)
 	if err != nil {
 		if c.Verbose {
-			c.Log(caller, "CRUD insert filter by struct:%v,sql:%v, result is fail:%v", reflect.TypeOf(v), sql, err)
+			c.Log(caller, "CRUD upsert filter by struct:%v,sql:%v, result is fail:%v", reflect.TypeOf(v), sql, err)
 		}
 		return
 	}
 	if c.Verbose {
-		c.Log(caller, "CRUD insert filter by struct:%v,sql:%v, result is success", reflect.TypeOf(v), sql)
+		c.Log(caller, "CRUD upsert filter by struct:%v,sql:%v, result is success", reflect.TypeOf(v), sql)
 	}
 	return
 }
 
-func UpdateArgs(v interface{}, filter string, args []interface{}) (table string, sets []string, args_ []interface{}) {
-	table, sets, args_ = Default.updateArgs(1, v, filter, args)
+func UpdateArgs(v interface{}, filter interface{}, args []interface{}) (table string, sets []string, args_ []interface{}) {
+	table, sets, args_ = Default.updateArgs(1, Default.autoTimeCopy(v, false, true), filterString(filter), args)
 	return
 }
 
-func (c *CRUD) UpdateArgs(v interface{}, filter string, args []interface{}) (table string, sets []string, args_ []interface{}) {
-	table, sets, args_ = c.updateArgs(1, v, filter, args)
+func (c *CRUD) UpdateArgs(v interface{}, filter interface{}, args []interface{}) (table string, sets []string, args_ []interface{}) {
+	table, sets, args_ = c.updateArgs(1, c.autoTimeCopy(v, false, true), filterString(filter), args)
 	return
 }
 
 func (c *CRUD) updateArgs(caller int, v interface{}, filter string, args []interface{}) (table string, sets []string, args_ []interface{}) {
 	args_ = args
+	version, _ := c.versionField(v)
+	autoFields, autoValues := c.autoTimeFields(v, "update")
+	called := map[string]bool{}
 	table = c.FilterFieldCall("update", v, filter, func(fieldName, fieldFunc string, field reflect.StructField, value interface{}) {
-		args_ = append(args_, c.ParmConv("update", fieldName, fieldFunc, field, value))
-		sets = append(sets, fmt.Sprintf("%v="+c.ArgFormat, fieldName, len(args_)))
+		if version != nil && fieldName == version.Name {
+			return
+		}
+		called[fieldName] = true
+		args_ = append(args_, c.redactArg(fieldName, field, c.ParmConv("update", fieldName, fieldFunc, field, value)))
+		sets = append(sets, c.quote(fieldName)+"="+c.placeholder(len(args_)))
 	})
+	for i, field := range autoFields {
+		if called[field.Name] {
+			continue
+		}
+		args_ = append(args_, c.redactArg(field.Name, field.Field, c.ParmConv("update", field.Name, "", field.Field, autoValues[i].Addr().Interface())))
+		sets = append(sets, c.quote(field.Name)+"="+c.placeholder(len(args_)))
+	}
+	if version != nil {
+		sets = append(sets, fmt.Sprintf("%v=%v+1", c.quote(version.Name), c.quote(version.Name)))
+	}
 	if c.Verbose {
-		c.Log(caller, "CRUD generate update args by struct:%v,filter:%v, result is sets:%v,args:%v", reflect.TypeOf(v), filter, sets, jsonString(args_))
+		c.Log(caller, "CRUD generate update args by struct:%v,filter:%v, result is sets:%v,args:%v", reflect.TypeOf(v), filter, sets, jsonString(c.redactArgs(v, args_)))
 	}
 	return
 }
 
-func UpdateSQL(v interface{}, filter string, args []interface{}, suffix ...string) (sql string, args_ []interface{}) {
-	sql, args_ = Default.updateSQL(1, v, filter, args, suffix...)
+func UpdateSQL(v interface{}, filter interface{}, args []interface{}, suffix ...string) (sql string, args_ []interface{}) {
+	sql, args_ = Default.updateSQL(1, Default.autoTimeCopy(v, false, true), filterString(filter), args, suffix...)
 	return
 }
 
-func (c *CRUD) UpdateSQL(v interface{}, filter string, args []interface{}, suffix ...string) (sql string, args_ []interface{}) {
-	sql, args_ = c.updateSQL(1, v, filter, args, suffix...)
+func (c *CRUD) UpdateSQL(v interface{}, filter interface{}, args []interface{}, suffix ...string) (sql string, args_ []interface{}) {
+	sql, args_ = c.updateSQL(1, c.autoTimeCopy(v, false, true), filterString(filter), args, suffix...)
 	return
 }
 
 func (c *CRUD) updateSQL(caller int, v interface{}, filter string, args []interface{}, suffix ...string) (sql string, args_ []interface{}) {
 	table, sets, args_ := c.updateArgs(caller+1, v, filter, args)
-	sql = fmt.Sprintf(`update %v set %v %v`, table, strings.Join(sets, ","), strings.Join(suffix, " "))
+	sql = fmt.Sprintf(`update %v set %v`, table, strings.Join(sets, ","))
+	if len(suffix) > 0 {
+		sql += " " + strings.Join(suffix, " ")
+	}
 	if c.Verbose {
-		c.Log(caller, "CRUD generate update sql by struct:%v,filter:%v, result is sql:%v,args:%v", reflect.TypeOf(v), filter, sql, jsonString(args_))
+		c.Log(caller, "CRUD generate update sql by struct:%v,filter:%v, result is sql:%v,args:%v", reflect.TypeOf(v), filter, sql, jsonString(c.redactArgs(v, args_)))
 	}
 	return
 }
 
 func Update(queryer interface{}, ctx context.Context, v interface{}, sql string, where []string, sep string, args []interface{}) (affected int64, err error) {
+	defer Default.recoverError(&err)
 	affected, err = Default.update(1, queryer, ctx, v, sql, where, sep, args)
 	return
 }
 
 func (c *CRUD) Update(queryer interface{}, ctx context.Context, v interface{}, sql string, where []string, sep string, args []interface{}) (affected int64, err error) {
+	defer c.recoverError(&err)
 	affected, err = c.update(1, queryer, ctx, v, sql, where, sep, args)
 	return
 }
 
 func (c *CRUD) update(caller int, queryer interface{}, ctx context.Context, v interface{}, sql string, where []string, sep string, args []interface{}) (affected int64, err error) {
-	sql = c.joinWhere(caller+1, sql, where, sep)
-	_, affected, err = c.queryerExec(queryer, ctx, sql, args)
+	where, args, sep, err = c.updateScope(ctx, v, where, args, sep)
+	if err != nil {
+		return
+	}
+	where, args, sep, versioned := c.versionWhere(v, where, args, sep)
+	sql, affected, err = c.execWhere(caller+1, queryer, ctx, OpUpdate, v, sql, where, sep, args)
+	if err == nil && versioned {
+		err = c.versionCheck(v, affected)
+	}
 	if err != nil {
 		if c.Verbose {
-			c.Log(caller, "CRUD update by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(args), err)
+			c.Log(caller, "CRUD update by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), err)
 		}
 		return
 	}
 	if c.Verbose {
-		c.Log(caller, "CRUD update by struct:%v,sql:%v,args:%v, result is success affected:%v", reflect.TypeOf(v), sql, jsonString(args), affected)
+		c.Log(caller, "CRUD update by struct:%v,sql:%v,args:%v, result is success affected:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), affected)
 	}
 	return
 }
 
 func UpdateRow(queryer interface{}, ctx context.Context, v interface{}, sql string, where []string, sep string, args []interface{}) (err error) {
+	defer Default.recoverError(&err)
 	err = Default.updateRow(1, queryer, ctx, v, sql, where, sep, args)
 	return
 }
 
 func (c *CRUD) UpdateRow(queryer interface{}, ctx context.Context, v interface{}, sql string, where []string, sep string, args []interface{}) (err error) {
+	defer c.recoverError(&err)
 	err = c.updateRow(1, queryer, ctx, v, sql, where, sep, args)
 	return
 }
@@ -819,38 +1666,45 @@ func (c *CRUD) updateRow(caller int, queryer interface{}, ctx context.Context, v
 }
 
 func UpdateSet(queryer interface{}, ctx context.Context, v interface{}, sets, where []string, sep string, args []interface{}) (affected int64, err error) {
+	defer Default.recoverError(&err)
 	affected, err = Default.updateSet(1, queryer, ctx, v, sets, where, sep, args)
 	return
 }
 
 func (c *CRUD) UpdateSet(queryer interface{}, ctx context.Context, v interface{}, sets, where []string, sep string, args []interface{}) (affected int64, err error) {
+	defer c.recoverError(&err)
 	affected, err = c.updateSet(1, queryer, ctx, v, sets, where, sep, args)
 	return
 }
 
 func (c *CRUD) updateSet(caller int, queryer interface{}, ctx context.Context, v interface{}, sets, where []string, sep string, args []interface{}) (affected int64, err error) {
+	where, args, sep, err = c.updateScope(ctx, v, where, args, sep)
+	if err != nil {
+		return
+	}
 	table := c.Table(v)
 	sql := fmt.Sprintf(`update %v set %v`, table, strings.Join(sets, ","))
-	sql = c.joinWhere(caller+1, sql, where, sep)
-	_, affected, err = c.queryerExec(queryer, ctx, sql, args)
+	sql, affected, err = c.execWhere(caller+1, queryer, ctx, OpUpdate, v, sql, where, sep, args)
 	if err != nil {
 		if c.Verbose {
-			c.Log(caller, "CRUD update by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(args), err)
+			c.Log(caller, "CRUD update by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), err)
 		}
 		return
 	}
 	if c.Verbose {
-		c.Log(caller, "CRUD update by struct:%v,sql:%v,args:%v, result is success affected:%v", reflect.TypeOf(v), sql, jsonString(args), affected)
+		c.Log(caller, "CRUD update by struct:%v,sql:%v,args:%v, result is success affected:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), affected)
 	}
 	return
 }
 
 func UpdateRowSet(queryer interface{}, ctx context.Context, v interface{}, sets, where []string, sep string, args []interface{}) (err error) {
+	defer Default.recoverError(&err)
 	err = Default.updateRowSet(1, queryer, ctx, v, sets, where, sep, args)
 	return
 }
 
 func (c *CRUD) UpdateRowSet(queryer interface{}, ctx context.Context, v interface{}, sets, where []string, sep string, args []interface{}) (err error) {
+	defer c.recoverError(&err)
 	err = c.updateRowSet(1, queryer, ctx, v, sets, where, sep, args)
 	return
 }
@@ -863,39 +1717,51 @@ func (c *CRUD) updateRowSet(caller int, queryer interface{}, ctx context.Context
 	return
 }
 
-func UpdateFilter(queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}) (affected int64, err error) {
-	affected, err = Default.updateFilter(1, queryer, ctx, v, filter, where, sep, args)
+func UpdateFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, where []string, sep string, args []interface{}) (affected int64, err error) {
+	defer Default.recoverError(&err)
+	affected, err = Default.updateFilter(1, queryer, ctx, v, filterString(filter), where, sep, args)
 	return
 }
 
-func (c *CRUD) UpdateFilter(queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}) (affected int64, err error) {
-	affected, err = c.updateFilter(1, queryer, ctx, v, filter, where, sep, args)
+func (c *CRUD) UpdateFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, where []string, sep string, args []interface{}) (affected int64, err error) {
+	defer c.recoverError(&err)
+	affected, err = c.updateFilter(1, queryer, ctx, v, filterString(filter), where, sep, args)
 	return
 }
 
 func (c *CRUD) updateFilter(caller int, queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}) (affected int64, err error) {
+	c.autoTimeUpdate(v)
 	sql, args := c.updateSQL(caller+1, v, filter, args)
-	sql = c.joinWhere(caller+1, sql, where, sep)
-	_, affected, err = c.queryerExec(queryer, ctx, sql, args)
+	where, args, sep, err = c.updateScope(ctx, v, where, args, sep)
+	if err != nil {
+		return
+	}
+	where, args, sep, versioned := c.versionWhere(v, where, args, sep)
+	sql, affected, err = c.execWhere(caller+1, queryer, ctx, OpUpdate, v, sql, where, sep, args)
+	if err == nil && versioned {
+		err = c.versionCheck(v, affected)
+	}
 	if err != nil {
 		if c.Verbose {
-			c.Log(caller, "CRUD update filter by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(args), err)
+			c.Log(caller, "CRUD update filter by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), err)
 		}
 		return
 	}
 	if c.Verbose {
-		c.Log(caller, "CRUD update filter by struct:%v,sql:%v,args:%v, result is success affected:%v", reflect.TypeOf(v), sql, jsonString(args), affected)
+		c.Log(caller, "CRUD update filter by struct:%v,sql:%v,args:%v, result is success affected:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), affected)
 	}
 	return
 }
 
-func UpdateRowFilter(queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}) (err error) {
-	err = Default.updateRowFilter(1, queryer, ctx, v, filter, where, sep, args)
+func UpdateRowFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, where []string, sep string, args []interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.updateRowFilter(1, queryer, ctx, v, filterString(filter), where, sep, args)
 	return
 }
 
-func (c *CRUD) UpdateRowFilter(queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}) (err error) {
-	err = c.updateRowFilter(1, queryer, ctx, v, filter, where, sep, args)
+func (c *CRUD) UpdateRowFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, where []string, sep string, args []interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.updateRowFilter(1, queryer, ctx, v, filterString(filter), where, sep, args)
 	return
 }
 
@@ -907,39 +1773,52 @@ func (c *CRUD) updateRowFilter(caller int, queryer interface{}, ctx context.Cont
 	return
 }
 
-func UpdateWheref(queryer interface{}, ctx context.Context, v interface{}, filter, formats string, args ...interface{}) (affected int64, err error) {
-	affected, err = Default.updateWheref(1, queryer, ctx, v, filter, formats, args...)
+func UpdateWheref(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, formats string, args ...interface{}) (affected int64, err error) {
+	defer Default.recoverError(&err)
+	affected, err = Default.updateWheref(1, queryer, ctx, v, filterString(filter), formats, args...)
 	return
 }
 
-func (c *CRUD) UpdateWheref(queryer interface{}, ctx context.Context, v interface{}, filter, formats string, args ...interface{}) (affected int64, err error) {
-	affected, err = c.updateWheref(1, queryer, ctx, v, filter, formats, args...)
+func (c *CRUD) UpdateWheref(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, formats string, args ...interface{}) (affected int64, err error) {
+	defer c.recoverError(&err)
+	affected, err = c.updateWheref(1, queryer, ctx, v, filterString(filter), formats, args...)
 	return
 }
 
 func (c *CRUD) updateWheref(caller int, queryer interface{}, ctx context.Context, v interface{}, filter, formats string, args ...interface{}) (affected int64, err error) {
+	c.autoTimeUpdate(v)
 	sql, sqlArgs := c.updateSQL(caller+1, v, filter, nil)
-	sql, sqlArgs = c.joinWheref(caller+1, sql, sqlArgs, formats, args...)
-	_, affected, err = c.queryerExec(queryer, ctx, sql, sqlArgs)
+	where, sqlArgs, sep := c.wherefArgs(sqlArgs, formats, args...)
+	where, sqlArgs, sep, err = c.updateScope(ctx, v, where, sqlArgs, sep)
+	if err != nil {
+		return
+	}
+	where, sqlArgs, sep, versioned := c.versionWhere(v, where, sqlArgs, sep)
+	sql, affected, err = c.execWhere(caller+1, queryer, ctx, OpUpdate, v, sql, where, sep, sqlArgs)
+	if err == nil && versioned {
+		err = c.versionCheck(v, affected)
+	}
 	if err != nil {
 		if c.Verbose {
-			c.Log(caller, "CRUD update wheref by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(sqlArgs), err)
+			c.Log(caller, "CRUD update wheref by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, sqlArgs)), err)
 		}
 		return
 	}
 	if c.Verbose {
-		c.Log(caller, "CRUD update wheref by struct:%v,sql:%v,args:%v, result is success affected:%v", reflect.TypeOf(v), sql, jsonString(sqlArgs), affected)
+		c.Log(caller, "CRUD update wheref by struct:%v,sql:%v,args:%v, result is success affected:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, sqlArgs)), affected)
 	}
 	return
 }
 
-func UpdateRowWheref(queryer interface{}, ctx context.Context, v interface{}, filter, formats string, args ...interface{}) (err error) {
-	err = Default.updateRowWheref(1, queryer, ctx, v, filter, formats, args...)
+func UpdateRowWheref(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, formats string, args ...interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.updateRowWheref(1, queryer, ctx, v, filterString(filter), formats, args...)
 	return
 }
 
-func (c *CRUD) UpdateRowWheref(queryer interface{}, ctx context.Context, v interface{}, filter, formats string, args ...interface{}) (err error) {
-	err = c.updateRowWheref(1, queryer, ctx, v, filter, formats, args...)
+func (c *CRUD) UpdateRowWheref(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, formats string, args ...interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.updateRowWheref(1, queryer, ctx, v, filterString(filter), formats, args...)
 	return
 }
 
@@ -951,23 +1830,267 @@ func (c *CRUD) updateRowWheref(caller int, queryer interface{}, ctx context.Cont
 	return
 }
 
-func QueryField(v interface{}, filter string) (table string, fields []string) {
-	table, fields = Default.queryField(1, v, filter)
+func DeleteSQL(v interface{}, suffix ...string) (sql string) {
+	sql = Default.deleteSQL(1, v, suffix...)
+	return
+}
+
+func (c *CRUD) DeleteSQL(v interface{}, suffix ...string) (sql string) {
+	sql = c.deleteSQL(1, v, suffix...)
+	return
+}
+
+func (c *CRUD) deleteSQL(caller int, v interface{}, suffix ...string) (sql string) {
+	table := c.Table(v)
+	sql = fmt.Sprintf(`delete from %v`, table)
+	if len(suffix) > 0 {
+		sql += " " + strings.Join(suffix, " ")
+	}
+	if c.Verbose {
+		c.Log(caller, "CRUD generate delete sql by struct:%v, result is sql:%v", reflect.TypeOf(v), sql)
+	}
+	return
+}
+
+func Delete(queryer interface{}, ctx context.Context, v interface{}, sql string, where []string, sep string, args []interface{}) (affected int64, err error) {
+	defer Default.recoverError(&err)
+	affected, err = Default.delete(1, queryer, ctx, v, sql, where, sep, args)
+	return
+}
+
+func (c *CRUD) Delete(queryer interface{}, ctx context.Context, v interface{}, sql string, where []string, sep string, args []interface{}) (affected int64, err error) {
+	defer c.recoverError(&err)
+	affected, err = c.delete(1, queryer, ctx, v, sql, where, sep, args)
+	return
+}
+
+func (c *CRUD) delete(caller int, queryer interface{}, ctx context.Context, v interface{}, sql string, where []string, sep string, args []interface{}) (affected int64, err error) {
+	where, args, sep, err = c.updateScope(ctx, v, where, args, sep)
+	if err != nil {
+		return
+	}
+	sql, affected, err = c.execWhere(caller+1, queryer, ctx, OpDelete, v, sql, where, sep, args)
+	if err != nil {
+		if c.Verbose {
+			c.Log(caller, "CRUD delete by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), err)
+		}
+		return
+	}
+	if c.Verbose {
+		c.Log(caller, "CRUD delete by struct:%v,sql:%v,args:%v, result is success affected:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), affected)
+	}
+	return
+}
+
+func DeleteRow(queryer interface{}, ctx context.Context, v interface{}, sql string, where []string, sep string, args []interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.deleteRow(1, queryer, ctx, v, sql, where, sep, args)
+	return
+}
+
+func (c *CRUD) DeleteRow(queryer interface{}, ctx context.Context, v interface{}, sql string, where []string, sep string, args []interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.deleteRow(1, queryer, ctx, v, sql, where, sep, args)
+	return
+}
+
+func (c *CRUD) deleteRow(caller int, queryer interface{}, ctx context.Context, v interface{}, sql string, where []string, sep string, args []interface{}) (err error) {
+	affected, err := c.delete(caller+1, queryer, ctx, v, sql, where, sep, args)
+	if err == nil && affected < 1 {
+		err = c.getErrNoRows()
+	}
+	return
+}
+
+func DeleteFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, where []string, sep string, args []interface{}) (affected int64, err error) {
+	defer Default.recoverError(&err)
+	affected, err = Default.deleteFilter(1, queryer, ctx, v, filterString(filter), where, sep, args)
+	return
+}
+
+func (c *CRUD) DeleteFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, where []string, sep string, args []interface{}) (affected int64, err error) {
+	defer c.recoverError(&err)
+	affected, err = c.deleteFilter(1, queryer, ctx, v, filterString(filter), where, sep, args)
+	return
+}
+
+func (c *CRUD) deleteFilter(caller int, queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}) (affected int64, err error) {
+	sql := c.deleteSoftSQL(caller+1, ctx, v)
+	filterWhere, args := c.FilterWhere(args, v, filter)
+	where = append(append([]string{}, where...), filterWhere...)
+	where, args, sep, err = c.scopeWhere(ctx, v, where, args, sep)
+	if err != nil {
+		return
+	}
+	sql, affected, err = c.execWhere(caller+1, queryer, ctx, OpDelete, v, sql, where, sep, args)
+	if err != nil {
+		if c.Verbose {
+			c.Log(caller, "CRUD delete filter by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), err)
+		}
+		return
+	}
+	if c.Verbose {
+		c.Log(caller, "CRUD delete filter by struct:%v,sql:%v,args:%v, result is success affected:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), affected)
+	}
+	return
+}
+
+func DeleteRowFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, where []string, sep string, args []interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.deleteRowFilter(1, queryer, ctx, v, filterString(filter), where, sep, args)
+	return
+}
+
+func (c *CRUD) DeleteRowFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, where []string, sep string, args []interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.deleteRowFilter(1, queryer, ctx, v, filterString(filter), where, sep, args)
+	return
+}
+
+func (c *CRUD) deleteRowFilter(caller int, queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}) (err error) {
+	affected, err := c.deleteFilter(caller+1, queryer, ctx, v, filter, where, sep, args)
+	if err == nil && affected < 1 {
+		err = c.getErrNoRows()
+	}
+	return
+}
+
+func DeleteWheref(queryer interface{}, ctx context.Context, v interface{}, formats string, args ...interface{}) (affected int64, err error) {
+	defer Default.recoverError(&err)
+	affected, err = Default.deleteWheref(1, queryer, ctx, v, formats, args...)
+	return
+}
+
+func (c *CRUD) DeleteWheref(queryer interface{}, ctx context.Context, v interface{}, formats string, args ...interface{}) (affected int64, err error) {
+	defer c.recoverError(&err)
+	affected, err = c.deleteWheref(1, queryer, ctx, v, formats, args...)
+	return
+}
+
+func (c *CRUD) deleteWheref(caller int, queryer interface{}, ctx context.Context, v interface{}, formats string, args ...interface{}) (affected int64, err error) {
+	sql := c.deleteSoftSQL(caller+1, ctx, v)
+	where, sqlArgs, sep := c.wherefArgs(nil, formats, args...)
+	where, sqlArgs, sep, err = c.scopeWhere(ctx, v, where, sqlArgs, sep)
+	if err != nil {
+		return
+	}
+	sql, affected, err = c.execWhere(caller+1, queryer, ctx, OpDelete, v, sql, where, sep, sqlArgs)
+	if err != nil {
+		if c.Verbose {
+			c.Log(caller, "CRUD delete wheref by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, sqlArgs)), err)
+		}
+		return
+	}
+	if c.Verbose {
+		c.Log(caller, "CRUD delete wheref by struct:%v,sql:%v,args:%v, result is success affected:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, sqlArgs)), affected)
+	}
+	return
+}
+
+func DeleteRowWheref(queryer interface{}, ctx context.Context, v interface{}, formats string, args ...interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.deleteRowWheref(1, queryer, ctx, v, formats, args...)
+	return
+}
+
+func (c *CRUD) DeleteRowWheref(queryer interface{}, ctx context.Context, v interface{}, formats string, args ...interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.deleteRowWheref(1, queryer, ctx, v, formats, args...)
+	return
+}
+
+func (c *CRUD) deleteRowWheref(caller int, queryer interface{}, ctx context.Context, v interface{}, formats string, args ...interface{}) (err error) {
+	affected, err := c.deleteWheref(caller+1, queryer, ctx, v, formats, args...)
+	if err == nil && affected < 1 {
+		err = c.getErrNoRows()
+	}
+	return
+}
+
+// DeleteUnifySQL will return sql by unify struct without context, the tenant scope is not applied, use DeleteUnifySQLContext to apply scope in ctx and get error
+func DeleteUnifySQL(v interface{}) (sql string, args []interface{}) {
+	sql, args = Default.DeleteUnifySQL(v)
+	return
+}
+
+// DeleteUnifySQL will return sql by unify struct without context, the tenant scope is not applied, use DeleteUnifySQLContext to apply scope in ctx and get error
+func (c *CRUD) DeleteUnifySQL(v interface{}) (sql string, args []interface{}) {
+	sql, args, err := c.DeleteUnifySQLContext(WithoutTenant(context.Background()), v)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// DeleteUnifySQLContext will return sql by unify struct and the model scope in ctx like tenant
+func DeleteUnifySQLContext(ctx context.Context, v interface{}) (sql string, args []interface{}, err error) {
+	sql, args, err = Default.DeleteUnifySQLContext(ctx, v)
+	return
+}
+
+// DeleteUnifySQLContext will return sql by unify struct and the model scope in ctx like tenant
+func (c *CRUD) DeleteUnifySQLContext(ctx context.Context, v interface{}) (sql string, args []interface{}, err error) {
+	defer c.recoverError(&err)
+	sql, args, err = c.deleteUnifySQL(1, ctx, v)
+	return
+}
+
+func (c *CRUD) deleteUnifySQL(caller int, ctx context.Context, v interface{}) (sql string, args []interface{}, err error) {
+	c.unifyCheck(v, "Model")
+	sql = c.deleteSoftSQL(caller+1, ctx, c.unifyModel(v))
+	sql, args, err = c.joinWhereUnify(caller+1, ctx, sql, nil, v)
+	return
+}
+
+func DeleteUnify(queryer interface{}, ctx context.Context, v interface{}) (affected int64, err error) {
+	defer Default.recoverError(&err)
+	affected, err = Default.deleteUnify(1, queryer, ctx, v)
+	return
+}
+
+func (c *CRUD) DeleteUnify(queryer interface{}, ctx context.Context, v interface{}) (affected int64, err error) {
+	defer c.recoverError(&err)
+	affected, err = c.deleteUnify(1, queryer, ctx, v)
+	return
+}
+
+func (c *CRUD) deleteUnify(caller int, queryer interface{}, ctx context.Context, v interface{}) (affected int64, err error) {
+	c.unifyCheck(v, "Model")
+	model := c.unifyModel(v)
+	sql := c.deleteSoftSQL(caller+1, ctx, model)
+	where, args, sep, err := c.whereUnify(ctx, v, nil)
+	if err != nil {
+		return
+	}
+	sql, affected, err = c.execWhere(caller+1, queryer, ctx, OpDelete, model, sql, where, sep, args)
+	if err != nil {
+		if c.Verbose {
+			c.Log(caller, "CRUD delete unify by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), err)
+		}
+		return
+	}
+	if c.Verbose {
+		c.Log(caller, "CRUD delete unify by struct:%v,sql:%v,args:%v, result is success affected:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), affected)
+	}
+	return
+}
+
+func QueryField(v interface{}, filter interface{}) (table string, fields []string) {
+	table, fields = Default.queryField(1, v, filterString(filter))
 	return
 }
 
-func (c *CRUD) QueryField(v interface{}, filter string) (table string, fields []string) {
-	table, fields = c.queryField(1, v, filter)
+func (c *CRUD) QueryField(v interface{}, filter interface{}) (table string, fields []string) {
+	table, fields = c.queryField(1, v, filterString(filter))
 	return
 }
 
 func (c *CRUD) queryField(caller int, v interface{}, filter string) (table string, fields []string) {
-	table = c.FilterFieldCall("query", v, filter, func(fieldName, fieldFunc string, field reflect.StructField, value interface{}) {
-		conv := field.Tag.Get("conv")
-		if len(fieldFunc) > 0 {
-			fields = append(fields, fmt.Sprintf("%v(%v%v)", fieldFunc, fieldName, conv))
+	table = c.filterFieldCall("query", v, filter, func(field *fieldMeta, value interface{}) {
+		if len(field.Func) > 0 {
+			fields = append(fields, fmt.Sprintf("%v(%v%v)", field.Func, c.quote(field.Name), field.Conv))
 		} else {
-			fields = append(fields, fmt.Sprintf("%v%v", fieldName, conv))
+			fields = append(fields, fmt.Sprintf("%v%v", c.quote(field.Name), field.Conv))
 		}
 	})
 	if c.Verbose {
@@ -976,13 +2099,13 @@ func (c *CRUD) queryField(caller int, v interface{}, filter string) (table strin
 	return
 }
 
-func QuerySQL(v interface{}, filter string, suffix ...string) (sql string) {
-	sql = Default.querySQL(1, v, "", filter, suffix...)
+func QuerySQL(v interface{}, filter interface{}, suffix ...string) (sql string) {
+	sql = Default.querySQL(1, v, "", filterString(filter), suffix...)
 	return
 }
 
-func (c *CRUD) QuerySQL(v interface{}, filter string, suffix ...string) (sql string) {
-	sql = c.querySQL(1, v, "", filter, suffix...)
+func (c *CRUD) QuerySQL(v interface{}, filter interface{}, suffix ...string) (sql string) {
+	sql = c.querySQL(1, v, "", filterString(filter), suffix...)
 	return
 }
 
@@ -1001,17 +2124,36 @@ func (c *CRUD) querySQL(caller int, v interface{}, from, filter string, suffix .
 	return
 }
 
+// QueryUnifySQL will return sql by unify struct without context, the tenant scope is not applied, use QueryUnifySQLContext to apply scope in ctx and get error
 func QueryUnifySQL(v interface{}, field string) (sql string, args []interface{}) {
-	sql, args = Default.queryUnifySQL(1, v, field)
+	sql, args = Default.QueryUnifySQL(v, field)
 	return
 }
 
+// QueryUnifySQL will return sql by unify struct without context, the tenant scope is not applied, use QueryUnifySQLContext to apply scope in ctx and get error
 func (c *CRUD) QueryUnifySQL(v interface{}, field string) (sql string, args []interface{}) {
-	sql, args = c.queryUnifySQL(1, v, field)
+	sql, args, err := c.QueryUnifySQLContext(WithoutTenant(context.Background()), v, field)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// QueryUnifySQLContext will return sql by unify struct and the model scope in ctx like tenant
+func QueryUnifySQLContext(ctx context.Context, v interface{}, field string) (sql string, args []interface{}, err error) {
+	sql, args, err = Default.QueryUnifySQLContext(ctx, v, field)
+	return
+}
+
+// QueryUnifySQLContext will return sql by unify struct and the model scope in ctx like tenant
+func (c *CRUD) QueryUnifySQLContext(ctx context.Context, v interface{}, field string) (sql string, args []interface{}, err error) {
+	defer c.recoverError(&err)
+	sql, args, err = c.queryUnifySQL(1, ctx, v, field)
 	return
 }
 
-func (c *CRUD) queryUnifySQL(caller int, v interface{}, field string) (sql string, args []interface{}) {
+func (c *CRUD) queryUnifySQL(caller int, ctx context.Context, v interface{}, field string) (sql string, args []interface{}, err error) {
+	c.unifyCheck(v, "Model", field)
 	reflectValue := reflect.Indirect(reflect.ValueOf(v))
 	reflectType := reflectValue.Type()
 	modelValue := reflectValue.FieldByName("Model")
@@ -1047,19 +2189,22 @@ func (c *CRUD) queryUnifySQL(caller int, v interface{}, field string) (sql strin
 	} else {
 		sql = c.querySQL(caller+1, modelValue.Addr().Interface(), modelFrom, queryFilter)
 	}
-	sql, args = c.joinWhereUnify(caller+1, sql, nil, v)
+	sql, args, err = c.joinWhereKeyset(caller+1, ctx, sql, nil, v)
+	if err != nil {
+		return
+	}
 	sql += " " + queryGroup
-	sql = c.joinPageUnify(caller+1, sql, v)
+	sql, err = c.joinPageUnify(caller+1, sql, v)
 	return
 }
 
-func ScanArgs(v interface{}, filter string) (args []interface{}) {
-	args = Default.ScanArgs(v, filter)
+func ScanArgs(v interface{}, filter interface{}) (args []interface{}) {
+	args = Default.ScanArgs(v, filterString(filter))
 	return
 }
 
-func (c *CRUD) ScanArgs(v interface{}, filter string) (args []interface{}) {
-	c.FilterFieldCall("scan", v, filter, func(fieldName, fieldFunc string, field reflect.StructField, value interface{}) {
+func (c *CRUD) ScanArgs(v interface{}, filter interface{}) (args []interface{}) {
+	c.FilterFieldCall("scan", v, filterString(filter), func(fieldName, fieldFunc string, field reflect.StructField, value interface{}) {
 		args = append(args, c.ParmConv("scan", fieldName, fieldFunc, field, value))
 	})
 	return
@@ -1070,15 +2215,26 @@ func ScanUnifyDest(v interface{}, queryName string) (modelValue interface{}, que
 	return
 }
 
+// ScanUnifyDestErr will return ErrInvalidUnify instead of panic when v is not having Model or queryName field
+func ScanUnifyDestErr(v interface{}, queryName string) (modelValue interface{}, queryFilter string, dests []interface{}, err error) {
+	modelValue, queryFilter, dests, err = Default.ScanUnifyDestErr(v, queryName)
+	return
+}
+
+// ScanUnifyDestErr will return ErrInvalidUnify instead of panic when v is not having Model or queryName field
+func (c *CRUD) ScanUnifyDestErr(v interface{}, queryName string) (modelValue interface{}, queryFilter string, dests []interface{}, err error) {
+	defer c.recoverError(&err)
+	modelValue, queryFilter, dests = c.ScanUnifyDest(v, queryName)
+	return
+}
+
 func (c *CRUD) ScanUnifyDest(v interface{}, queryName string) (modelValue interface{}, queryFilter string, dests []interface{}) {
+	c.unifyCheck(v, "Model", queryName)
 	reflectValue := reflect.Indirect(reflect.ValueOf(v))
 	reflectType := reflectValue.Type()
 	modelValue = reflectValue.FieldByName("Model").Addr().Interface()
 	queryType, _ := reflectType.FieldByName(queryName)
 	queryValue := reflectValue.FieldByName(queryName)
-	if !queryValue.IsValid() {
-		panic(fmt.Sprintf("%v is not exits in %v", queryName, reflectType))
-	}
 	queryFilter = queryType.Tag.Get("filter")
 	queryNum := queryType.Type.NumField()
 	for i := 0; i < queryNum; i++ {
@@ -1313,27 +2469,37 @@ func (c *CRUD) destSet(value reflect.Value, filter string, dests ...interface{})
 	return
 }
 
-func Scan(rows Rows, v interface{}, filter string, dest ...interface{}) (err error) {
-	err = Default.Scan(rows, v, filter, dest...)
+func Scan(rows Rows, v interface{}, filter interface{}, dest ...interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.Scan(rows, v, filterString(filter), dest...)
+	return
+}
+
+func (c *CRUD) Scan(rows Rows, v interface{}, filter interface{}, dest ...interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.scan(rows, v, filterString(filter), nil, dest...)
 	return
 }
 
-func (c *CRUD) Scan(rows Rows, v interface{}, filter string, dest ...interface{}) (err error) {
+func (c *CRUD) scan(rows Rows, v interface{}, filter string, call func(value reflect.Value), dest ...interface{}) (err error) {
 	isPtr := reflect.ValueOf(v).Kind() == reflect.Ptr
 	isStruct := reflect.Indirect(reflect.ValueOf(v)).Kind() == reflect.Struct
 	for rows.Next() {
 		value := NewValue(v)
-		err = rows.Scan(c.ScanArgs(value.Interface(), filter)...)
+		err = rows.Scan(c.ScanArgs(value.Interface(), filterString(filter))...)
 		if err != nil {
 			break
 		}
 		if !isPtr || !isStruct {
 			value = reflect.Indirect(value)
 		}
-		err = c.destSet(value, filter, dest...)
+		err = c.destSet(value, filterString(filter), dest...)
 		if err != nil {
 			break
 		}
+		if call != nil {
+			call(value)
+		}
 	}
 	return
 }
@@ -1349,137 +2515,177 @@ func ScanUnifyTarget(rows Rows, v interface{}, target string) (err error) {
 }
 
 func (c *CRUD) ScanUnify(rows Rows, v interface{}) (err error) {
+	defer c.recoverError(&err)
 	err = c.scanUnify(rows, v, "Query")
 	return
 }
 
 func (c *CRUD) ScanUnifyTarget(rows Rows, v interface{}, target string) (err error) {
+	defer c.recoverError(&err)
 	err = c.scanUnify(rows, v, target)
 	return
 }
 
 func (c *CRUD) scanUnify(rows Rows, v interface{}, target string) (err error) {
 	modelValue, modelFilter, dests := c.ScanUnifyDest(v, target)
-	err = c.Scan(rows, modelValue, modelFilter, dests...)
+	keyset := c.keysetUnify(v)
+	if keyset == nil {
+		err = c.Scan(rows, modelValue, modelFilter, dests...)
+		return
+	}
+	var last reflect.Value
+	scanned := 0
+	err = c.scan(rows, modelValue, modelFilter, func(value reflect.Value) {
+		last = value
+		scanned++
+	}, dests...)
+	if err == nil {
+		err = c.keysetNext(keyset, last, scanned)
+	}
 	return
 }
 
-func Query(queryer interface{}, ctx context.Context, v interface{}, filter, sql string, args []interface{}, dest ...interface{}) (err error) {
-	err = Default.query(1, queryer, ctx, v, filter, sql, args, dest...)
+func Query(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, sql string, args []interface{}, dest ...interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.query(1, queryer, ctx, v, filterString(filter), sql, args, dest...)
 	return
 }
 
-func (c *CRUD) Query(queryer interface{}, ctx context.Context, v interface{}, filter, sql string, args []interface{}, dest ...interface{}) (err error) {
-	err = c.query(1, queryer, ctx, v, filter, sql, args, dest...)
+func (c *CRUD) Query(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, sql string, args []interface{}, dest ...interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.query(1, queryer, ctx, v, filterString(filter), sql, args, dest...)
 	return
 }
 
 func (c *CRUD) query(caller int, queryer interface{}, ctx context.Context, v interface{}, filter, sql string, args []interface{}, dest ...interface{}) (err error) {
-	rows, err := c.queryerQuery(queryer, ctx, sql, args)
+	rows, err := c.queryerQuery(queryer, ctx, OpQuery, v, sql, args)
 	if err != nil {
 		if c.Verbose {
-			c.Log(caller, "CRUD query by struct:%v,filter:%v,sql:%v,args:%v result is fail:%v", reflect.TypeOf(v), filter, sql, jsonString(args), err)
+			c.Log(caller, "CRUD query by struct:%v,filter:%v,sql:%v,args:%v result is fail:%v", reflect.TypeOf(v), filter, sql, jsonString(c.redactArgs(v, args)), err)
 		}
 		return
 	}
 	defer rows.Close()
 	if c.Verbose {
-		c.Log(caller, "CRUD query by struct:%v,filter:%v,sql:%v,args:%v result is success", reflect.TypeOf(v), filter, sql, jsonString(args))
+		c.Log(caller, "CRUD query by struct:%v,filter:%v,sql:%v,args:%v result is success", reflect.TypeOf(v), filter, sql, jsonString(c.redactArgs(v, args)))
 	}
 	err = c.Scan(rows, v, filter, dest...)
 	return
 }
 
-func QueryFilter(queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}, orderby string, offset, limit int, dest ...interface{}) (err error) {
-	err = Default.queryFilter(1, queryer, ctx, v, filter, where, sep, args, orderby, offset, limit, dest...)
+func QueryFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, where []string, sep string, args []interface{}, orderby string, offset, limit int, dest ...interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.queryFilter(1, queryer, ctx, v, filterString(filter), where, sep, args, orderby, offset, limit, dest...)
 	return
 }
 
-func (c *CRUD) QueryFilter(queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}, orderby string, offset, limit int, dest ...interface{}) (err error) {
-	err = c.queryFilter(1, queryer, ctx, v, filter, where, sep, args, orderby, offset, limit, dest...)
+func (c *CRUD) QueryFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, where []string, sep string, args []interface{}, orderby string, offset, limit int, dest ...interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.queryFilter(1, queryer, ctx, v, filterString(filter), where, sep, args, orderby, offset, limit, dest...)
 	return
 }
 
 func (c *CRUD) queryFilter(caller int, queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}, orderby string, offset, limit int, dest ...interface{}) (err error) {
 	sql := c.querySQL(caller+1, v, "", filter)
+	where, args, sep, err = c.scopeWhere(ctx, v, where, args, sep)
+	if err != nil {
+		return
+	}
 	sql = c.joinWhere(caller+1, sql, where, sep)
 	sql = c.joinPage(caller+1, sql, orderby, offset, limit)
 	err = c.query(caller+1, queryer, ctx, v, filter, sql, args, dest...)
 	return
 }
 
-func QueryWheref(queryer interface{}, ctx context.Context, v interface{}, filter, formats string, args []interface{}, orderby string, offset, limit int, dest ...interface{}) (err error) {
-	err = Default.queryWheref(1, queryer, ctx, v, filter, formats, args, orderby, offset, limit, dest...)
+func QueryWheref(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, formats string, args []interface{}, orderby string, offset, limit int, dest ...interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.queryWheref(1, queryer, ctx, v, filterString(filter), formats, args, orderby, offset, limit, dest...)
 	return
 }
 
-func (c *CRUD) QueryWheref(queryer interface{}, ctx context.Context, v interface{}, filter, formats string, args []interface{}, orderby string, offset, limit int, dest ...interface{}) (err error) {
-	err = c.queryWheref(1, queryer, ctx, v, filter, formats, args, orderby, offset, limit, dest...)
+func (c *CRUD) QueryWheref(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, formats string, args []interface{}, orderby string, offset, limit int, dest ...interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.queryWheref(1, queryer, ctx, v, filterString(filter), formats, args, orderby, offset, limit, dest...)
 	return
 }
 
 func (c *CRUD) queryWheref(caller int, queryer interface{}, ctx context.Context, v interface{}, filter, formats string, args []interface{}, orderby string, offset, limit int, dest ...interface{}) (err error) {
 	sql := c.querySQL(caller+1, v, "", filter)
-	sql, sqlArgs := c.joinWheref(caller+1, sql, nil, formats, args...)
+	sql, sqlArgs, err := c.joinWherefScope(caller+1, ctx, v, sql, nil, formats, args...)
+	if err != nil {
+		return
+	}
 	sql = c.joinPage(caller+1, sql, orderby, offset, limit)
 	err = c.query(caller+1, queryer, ctx, v, filter, sql, sqlArgs, dest...)
 	return
 }
 
 func QueryUnify(queryer interface{}, ctx context.Context, v interface{}) (err error) {
+	defer Default.recoverError(&err)
 	err = Default.queryUnify(1, queryer, ctx, v, "Query")
 	return
 }
 
 func QueryUnifyTarget(queryer interface{}, ctx context.Context, v interface{}, target string) (err error) {
+	defer Default.recoverError(&err)
 	err = Default.queryUnify(1, queryer, ctx, v, target)
 	return
 }
 
 func (c *CRUD) QueryUnify(queryer interface{}, ctx context.Context, v interface{}) (err error) {
+	defer c.recoverError(&err)
 	err = c.queryUnify(1, queryer, ctx, v, "Query")
 	return
 }
 
 func (c *CRUD) QueryUnifyTarget(queryer interface{}, ctx context.Context, v interface{}, target string) (err error) {
+	defer c.recoverError(&err)
 	err = c.queryUnify(1, queryer, ctx, v, target)
 	return
 }
 
 func (c *CRUD) queryUnify(caller int, queryer interface{}, ctx context.Context, v interface{}, target string) (err error) {
-	sql, args := c.queryUnifySQL(caller+1, v, target)
-	rows, err := c.queryerQuery(queryer, ctx, sql, args)
+	sql, args, err := c.queryUnifySQL(caller+1, ctx, v, target)
+	if err != nil {
+		if c.Verbose {
+			c.Log(caller, "CRUD query unify by struct:%v result is fail:%v", reflect.TypeOf(v), err)
+		}
+		return
+	}
+	rows, err := c.queryerQuery(queryer, ctx, OpUnify, v, sql, args)
 	if err != nil {
 		if c.Verbose {
-			c.Log(caller, "CRUD query unify by struct:%v,sql:%v,args:%v result is fail:%v", reflect.TypeOf(v), sql, jsonString(args), err)
+			c.Log(caller, "CRUD query unify by struct:%v,sql:%v,args:%v result is fail:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), err)
 		}
 		return
 	}
 	defer rows.Close()
 	if c.Verbose {
-		c.Log(caller, "CRUD query unify by struct:%v,sql:%v,args:%v result is success", reflect.TypeOf(v), sql, jsonString(args))
+		c.Log(caller, "CRUD query unify by struct:%v,sql:%v,args:%v result is success", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)))
 	}
 	err = c.scanUnify(rows, v, target)
 	return
 }
 
-func ScanRow(row Row, v interface{}, filter string, dest ...interface{}) (err error) {
-	err = Default.ScanRow(row, v, filter, dest...)
+func ScanRow(row Row, v interface{}, filter interface{}, dest ...interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.ScanRow(row, v, filterString(filter), dest...)
 	return
 }
 
-func (c *CRUD) ScanRow(row Row, v interface{}, filter string, dest ...interface{}) (err error) {
+func (c *CRUD) ScanRow(row Row, v interface{}, filter interface{}, dest ...interface{}) (err error) {
+	defer c.recoverError(&err)
 	isPtr := reflect.ValueOf(v).Kind() == reflect.Ptr
 	isStruct := reflect.Indirect(reflect.ValueOf(v)).Kind() == reflect.Struct
 	value := NewValue(v)
-	err = row.Scan(c.ScanArgs(value.Interface(), filter)...)
+	err = row.Scan(c.ScanArgs(value.Interface(), filterString(filter))...)
 	if err != nil {
 		return
 	}
 	if !isPtr || !isStruct {
 		value = reflect.Indirect(value)
 	}
-	err = c.destSet(value, filter, dest...)
+	err = c.destSet(value, filterString(filter), dest...)
 	if err != nil {
 		return
 	}
@@ -1497,11 +2703,13 @@ func ScanRowUnifyTarget(row Row, v interface{}, target string) (err error) {
 }
 
 func (c *CRUD) ScanRowUnify(row Row, v interface{}) (err error) {
+	defer c.recoverError(&err)
 	err = c.scanRowUnify(row, v, "QueryRow")
 	return
 }
 
 func (c *CRUD) ScanRowUnifyTarget(row Row, v interface{}, target string) (err error) {
+	defer c.recoverError(&err)
 	err = c.scanRowUnify(row, v, target)
 	return
 }
@@ -1512,106 +2720,129 @@ func (c *CRUD) scanRowUnify(row Row, v interface{}, target string) (err error) {
 	return
 }
 
-func QueryRow(queryer interface{}, ctx context.Context, v interface{}, filter, sql string, args []interface{}, dest ...interface{}) (err error) {
-	err = Default.queryRow(1, queryer, ctx, v, filter, sql, args, dest...)
+func QueryRow(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, sql string, args []interface{}, dest ...interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.queryRow(1, queryer, ctx, v, filterString(filter), sql, args, dest...)
 	return
 }
 
-func (c *CRUD) QueryRow(queryer interface{}, ctx context.Context, v interface{}, filter, sql string, args []interface{}, dest ...interface{}) (err error) {
-	err = c.queryRow(1, queryer, ctx, v, filter, sql, args, dest...)
+func (c *CRUD) QueryRow(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, sql string, args []interface{}, dest ...interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.queryRow(1, queryer, ctx, v, filterString(filter), sql, args, dest...)
 	return
 }
 
 func (c *CRUD) queryRow(caller int, queryer interface{}, ctx context.Context, v interface{}, filter, sql string, args []interface{}, dest ...interface{}) (err error) {
-	err = c.ScanRow(c.queryerQueryRow(queryer, ctx, sql, args), v, filter, dest...)
+	err = c.ScanRow(c.queryerQueryRow(queryer, ctx, OpQuery, v, sql, args), v, filter, dest...)
 	if err != nil {
 		if c.Verbose {
-			c.Log(caller, "CRUD query by struct:%v,filter:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), filter, sql, jsonString(args), err)
+			c.Log(caller, "CRUD query by struct:%v,filter:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), filter, sql, jsonString(c.redactArgs(v, args)), err)
 		}
 		return
 	}
 	if c.Verbose {
-		c.Log(caller, "CRUD query by struct:%v,filter:%v,sql:%v,args:%v, result is success", reflect.TypeOf(v), filter, sql, jsonString(args))
+		c.Log(caller, "CRUD query by struct:%v,filter:%v,sql:%v,args:%v, result is success", reflect.TypeOf(v), filter, sql, jsonString(c.redactArgs(v, args)))
 	}
 	return
 }
 
-func QueryRowFilter(queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}, dest ...interface{}) (err error) {
-	err = Default.queryRowFilter(1, queryer, ctx, v, filter, where, sep, args, dest...)
+func QueryRowFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, where []string, sep string, args []interface{}, dest ...interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.queryRowFilter(1, queryer, ctx, v, filterString(filter), where, sep, args, dest...)
 	return
 }
 
-func (c *CRUD) QueryRowFilter(queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}, dest ...interface{}) (err error) {
-	err = c.queryRowFilter(1, queryer, ctx, v, filter, where, sep, args, dest...)
+func (c *CRUD) QueryRowFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, where []string, sep string, args []interface{}, dest ...interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.queryRowFilter(1, queryer, ctx, v, filterString(filter), where, sep, args, dest...)
 	return
 }
 
 func (c *CRUD) queryRowFilter(caller int, queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}, dest ...interface{}) (err error) {
 	sql := c.querySQL(caller+1, v, "", filter)
+	where, args, sep, err = c.scopeWhere(ctx, v, where, args, sep)
+	if err != nil {
+		return
+	}
 	sql = c.joinWhere(caller+1, sql, where, sep)
 	err = c.queryRow(caller+1, queryer, ctx, v, filter, sql, args, dest...)
 	return
 }
 
-func QueryRowWheref(queryer interface{}, ctx context.Context, v interface{}, filter, formats string, args []interface{}, dest ...interface{}) (err error) {
-	err = Default.queryRowWheref(1, queryer, ctx, v, filter, formats, args, dest...)
+func QueryRowWheref(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, formats string, args []interface{}, dest ...interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.queryRowWheref(1, queryer, ctx, v, filterString(filter), formats, args, dest...)
 	return
 }
 
-func (c *CRUD) QueryRowWheref(queryer interface{}, ctx context.Context, v interface{}, filter, formats string, args []interface{}, dest ...interface{}) (err error) {
-	err = c.queryRowWheref(1, queryer, ctx, v, filter, formats, args, dest...)
+func (c *CRUD) QueryRowWheref(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, formats string, args []interface{}, dest ...interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.queryRowWheref(1, queryer, ctx, v, filterString(filter), formats, args, dest...)
 	return
 }
 
 func (c *CRUD) queryRowWheref(caller int, queryer interface{}, ctx context.Context, v interface{}, filter, formats string, args []interface{}, dest ...interface{}) (err error) {
 	sql := c.querySQL(caller+1, v, "", filter)
-	sql, sqlArgs := c.joinWheref(caller+1, sql, nil, formats, args...)
+	sql, sqlArgs, err := c.joinWherefScope(caller+1, ctx, v, sql, nil, formats, args...)
+	if err != nil {
+		return
+	}
 	err = c.queryRow(caller+1, queryer, ctx, v, filter, sql, sqlArgs, dest...)
 	return
 }
 
 func QueryRowUnify(queryer interface{}, ctx context.Context, v interface{}) (err error) {
+	defer Default.recoverError(&err)
 	err = Default.queryRowUnify(1, queryer, ctx, v, "QueryRow")
 	return
 }
 
 func QueryRowUnifyTarget(queryer interface{}, ctx context.Context, v interface{}, target string) (err error) {
+	defer Default.recoverError(&err)
 	err = Default.queryRowUnify(1, queryer, ctx, v, target)
 	return
 }
 
 func (c *CRUD) QueryRowUnify(queryer interface{}, ctx context.Context, v interface{}) (err error) {
+	defer c.recoverError(&err)
 	err = c.queryRowUnify(1, queryer, ctx, v, "QueryRow")
 	return
 }
 
 func (c *CRUD) QueryRowUnifyTarget(queryer interface{}, ctx context.Context, v interface{}, target string) (err error) {
+	defer c.recoverError(&err)
 	err = c.queryRowUnify(1, queryer, ctx, v, target)
 	return
 }
 
 func (c *CRUD) queryRowUnify(caller int, queryer interface{}, ctx context.Context, v interface{}, target string) (err error) {
-	sql, args := c.queryUnifySQL(caller+1, v, target)
-	err = c.scanRowUnify(c.queryerQueryRow(queryer, ctx, sql, args), v, target)
+	sql, args, err := c.queryUnifySQL(caller+1, ctx, v, target)
+	if err != nil {
+		if c.Verbose {
+			c.Log(caller, "CRUD query unify row by struct:%v result is fail:%v", reflect.TypeOf(v), err)
+		}
+		return
+	}
+	err = c.scanRowUnify(c.queryerQueryRow(queryer, ctx, OpUnify, v, sql, args), v, target)
 	if err != nil {
 		if c.Verbose {
-			c.Log(caller, "CRUD query unify row by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(args), err)
+			c.Log(caller, "CRUD query unify row by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), err)
 		}
 		return
 	}
 	if c.Verbose {
-		c.Log(caller, "CRUD query unify row by struct:%v,sql:%v,args:%v, result is success", reflect.TypeOf(v), sql, jsonString(args))
+		c.Log(caller, "CRUD query unify row by struct:%v,sql:%v,args:%v, result is success", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)))
 	}
 	return
 }
 
-func CountSQL(v interface{}, filter string, suffix ...string) (sql string) {
-	sql = Default.countSQL(1, v, "", filter, suffix...)
+func CountSQL(v interface{}, filter interface{}, suffix ...string) (sql string) {
+	sql = Default.countSQL(1, v, "", filterString(filter), suffix...)
 	return
 }
 
-func (c *CRUD) CountSQL(v interface{}, filter string, suffix ...string) (sql string) {
-	sql = c.countSQL(1, v, "", filter, suffix...)
+func (c *CRUD) CountSQL(v interface{}, filter interface{}, suffix ...string) (sql string) {
+	sql = c.countSQL(1, v, "", filterString(filter), suffix...)
 	return
 }
 
@@ -1637,17 +2868,36 @@ func (c *CRUD) countSQL(caller int, v interface{}, from string, filter string, s
 	return
 }
 
+// CountUnifySQL will return sql by unify struct without context, the tenant scope is not applied, use CountUnifySQLContext to apply scope in ctx and get error
 func CountUnifySQL(v interface{}) (sql string, args []interface{}) {
-	sql, args = Default.countUnifySQL(1, v, "Count")
+	sql, args = Default.CountUnifySQL(v)
 	return
 }
 
+// CountUnifySQL will return sql by unify struct without context, the tenant scope is not applied, use CountUnifySQLContext to apply scope in ctx and get error
 func (c *CRUD) CountUnifySQL(v interface{}) (sql string, args []interface{}) {
-	sql, args = c.countUnifySQL(1, v, "Count")
+	sql, args, err := c.CountUnifySQLContext(WithoutTenant(context.Background()), v)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// CountUnifySQLContext will return sql by unify struct and the model scope in ctx like tenant
+func CountUnifySQLContext(ctx context.Context, v interface{}) (sql string, args []interface{}, err error) {
+	sql, args, err = Default.CountUnifySQLContext(ctx, v)
+	return
+}
+
+// CountUnifySQLContext will return sql by unify struct and the model scope in ctx like tenant
+func (c *CRUD) CountUnifySQLContext(ctx context.Context, v interface{}) (sql string, args []interface{}, err error) {
+	defer c.recoverError(&err)
+	sql, args, err = c.countUnifySQL(1, ctx, v, "Count")
 	return
 }
 
-func (c *CRUD) countUnifySQL(caller int, v interface{}, key string) (sql string, args []interface{}) {
+func (c *CRUD) countUnifySQL(caller int, ctx context.Context, v interface{}, key string) (sql string, args []interface{}, err error) {
+	c.unifyCheck(v, "Model", key)
 	reflectValue := reflect.Indirect(reflect.ValueOf(v))
 	reflectType := reflectValue.Type()
 	modelValue := reflectValue.FieldByName("Model").Addr().Interface()
@@ -1669,7 +2919,10 @@ func (c *CRUD) countUnifySQL(caller int, v interface{}, key string) (sql string,
 	} else {
 		sql = c.countSQL(caller+1, modelValue, modelFrom, queryFilter)
 	}
-	sql, args = c.joinWhereUnify(caller+1, sql, nil, v)
+	sql, args, err = c.joinWhereUnify(caller+1, ctx, sql, nil, v)
+	if err != nil {
+		return
+	}
 	sql += " " + queryGroup
 	return
 }
@@ -1695,6 +2948,7 @@ func (c *CRUD) CountUnifyDestTarget(v interface{}, target string) (modelValue in
 }
 
 func (c *CRUD) countUnifyDest(v interface{}, target string) (modelValue interface{}, queryFilter string, dests []interface{}) {
+	c.unifyCheck(v, "Model", target)
 	reflectValue := reflect.Indirect(reflect.ValueOf(v))
 	reflectType := reflectValue.Type()
 	modelValueList := []interface{}{
@@ -1719,60 +2973,73 @@ func (c *CRUD) countUnifyDest(v interface{}, target string) (modelValue interfac
 	return
 }
 
-func Count(queryer interface{}, ctx context.Context, v interface{}, filter, sql string, args []interface{}, dest ...interface{}) (err error) {
-	err = Default.count(1, queryer, ctx, v, filter, sql, args, dest...)
+func Count(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, sql string, args []interface{}, dest ...interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.count(1, queryer, ctx, v, filterString(filter), sql, args, dest...)
 	return
 }
 
-func (c *CRUD) Count(queryer interface{}, ctx context.Context, v interface{}, filter, sql string, args []interface{}, dest ...interface{}) (err error) {
-	err = c.count(1, queryer, ctx, v, filter, sql, args, dest...)
+func (c *CRUD) Count(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, sql string, args []interface{}, dest ...interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.count(1, queryer, ctx, v, filterString(filter), sql, args, dest...)
 	return
 }
 
 func (c *CRUD) count(caller int, queryer interface{}, ctx context.Context, v interface{}, filter, sql string, args []interface{}, dest ...interface{}) (err error) {
-	err = c.ScanRow(c.queryerQueryRow(queryer, ctx, sql, args), v, filter, dest...)
+	err = c.ScanRow(c.queryerQueryRow(queryer, ctx, OpCount, v, sql, args), v, filter, dest...)
 	if err != nil {
 		if c.Verbose {
-			c.Log(caller, "CRUD count by struct:%v,filter:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), filter, sql, jsonString(args), err)
+			c.Log(caller, "CRUD count by struct:%v,filter:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), filter, sql, jsonString(c.redactArgs(v, args)), err)
 		}
 		return
 	}
 	if c.Verbose {
-		c.Log(caller, "CRUD count by struct:%v,filter:%v,sql:%v,args:%v, result is success", reflect.TypeOf(v), filter, sql, jsonString(args))
+		c.Log(caller, "CRUD count by struct:%v,filter:%v,sql:%v,args:%v, result is success", reflect.TypeOf(v), filter, sql, jsonString(c.redactArgs(v, args)))
 	}
 	return
 }
 
-func CountFilter(queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}, suffix string, dest ...interface{}) (err error) {
-	err = Default.countFilter(1, queryer, ctx, v, filter, where, sep, args, suffix, dest...)
+func CountFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, where []string, sep string, args []interface{}, suffix string, dest ...interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.countFilter(1, queryer, ctx, v, filterString(filter), where, sep, args, suffix, dest...)
 	return
 }
 
-func (c *CRUD) CountFilter(queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}, suffix string, dest ...interface{}) (err error) {
-	err = c.countFilter(1, queryer, ctx, v, filter, where, sep, args, suffix, dest...)
+func (c *CRUD) CountFilter(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, where []string, sep string, args []interface{}, suffix string, dest ...interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.countFilter(1, queryer, ctx, v, filterString(filter), where, sep, args, suffix, dest...)
 	return
 }
 
 func (c *CRUD) countFilter(caller int, queryer interface{}, ctx context.Context, v interface{}, filter string, where []string, sep string, args []interface{}, suffix string, dest ...interface{}) (err error) {
 	sql := c.countSQL(caller+1, v, "", filter)
+	where, args, sep, err = c.scopeWhere(ctx, v, where, args, sep)
+	if err != nil {
+		return
+	}
 	sql = c.joinWhere(caller+1, sql, where, sep, suffix)
 	err = c.count(caller+1, queryer, ctx, v, filter, sql, args, dest...)
 	return
 }
 
-func CountWheref(queryer interface{}, ctx context.Context, v interface{}, filter, formats string, args []interface{}, suffix string, dest ...interface{}) (err error) {
-	err = Default.countWheref(1, queryer, ctx, v, filter, formats, args, suffix, dest...)
+func CountWheref(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, formats string, args []interface{}, suffix string, dest ...interface{}) (err error) {
+	defer Default.recoverError(&err)
+	err = Default.countWheref(1, queryer, ctx, v, filterString(filter), formats, args, suffix, dest...)
 	return
 }
 
-func (c *CRUD) CountWheref(queryer interface{}, ctx context.Context, v interface{}, filter, formats string, args []interface{}, suffix string, dest ...interface{}) (err error) {
-	err = c.countWheref(1, queryer, ctx, v, filter, formats, args, suffix, dest...)
+func (c *CRUD) CountWheref(queryer interface{}, ctx context.Context, v interface{}, filter interface{}, formats string, args []interface{}, suffix string, dest ...interface{}) (err error) {
+	defer c.recoverError(&err)
+	err = c.countWheref(1, queryer, ctx, v, filterString(filter), formats, args, suffix, dest...)
 	return
 }
 
 func (c *CRUD) countWheref(caller int, queryer interface{}, ctx context.Context, v interface{}, filter, formats string, args []interface{}, suffix string, dest ...interface{}) (err error) {
 	sql := c.countSQL(caller+1, v, "", filter)
-	sql, sqlArgs := c.joinWheref(caller+1, sql, nil, formats, args...)
+	sql, sqlArgs, err := c.joinWherefScope(caller+1, ctx, v, sql, nil, formats, args...)
+	if err != nil {
+		return
+	}
 	if len(suffix) > 0 {
 		sql += " " + suffix
 	}
@@ -1781,47 +3048,56 @@ func (c *CRUD) countWheref(caller int, queryer interface{}, ctx context.Context,
 }
 
 func CountUnify(queryer interface{}, ctx context.Context, v interface{}) (err error) {
+	defer Default.recoverError(&err)
 	err = Default.countUnify(1, queryer, ctx, v, "Count")
 	return
 }
 
 func CountUnifyTarget(queryer interface{}, ctx context.Context, v interface{}, target string) (err error) {
+	defer Default.recoverError(&err)
 	err = Default.countUnify(1, queryer, ctx, v, target)
 	return
 }
 
 func (c *CRUD) CountUnify(queryer interface{}, ctx context.Context, v interface{}) (err error) {
+	defer c.recoverError(&err)
 	err = c.countUnify(1, queryer, ctx, v, "Count")
 	return
 }
 
 func (c *CRUD) CountUnifyTarget(queryer interface{}, ctx context.Context, v interface{}, target string) (err error) {
+	defer c.recoverError(&err)
 	err = c.countUnify(1, queryer, ctx, v, target)
 	return
 }
 
 func (c *CRUD) countUnify(caller int, queryer interface{}, ctx context.Context, v interface{}, target string) (err error) {
-	sql, args := c.countUnifySQL(caller+1, v, target)
+	sql, args, err := c.countUnifySQL(caller+1, ctx, v, target)
+	if err != nil {
+		return
+	}
 	modelValue, queryFilter, dests := c.countUnifyDest(v, target)
-	err = c.ScanRow(c.queryerQueryRow(queryer, ctx, sql, args), modelValue, queryFilter, dests...)
+	err = c.ScanRow(c.queryerQueryRow(queryer, ctx, OpUnify, v, sql, args), modelValue, queryFilter, dests...)
 	if err != nil {
 		if c.Verbose {
-			c.Log(caller, "CRUD count unify by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(args), err)
+			c.Log(caller, "CRUD count unify by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)), err)
 		}
 		return
 	}
 	if c.Verbose {
-		c.Log(caller, "CRUD count unify by struct:%v,sql:%v,args:%v, result is success", reflect.TypeOf(v), sql, jsonString(args))
+		c.Log(caller, "CRUD count unify by struct:%v,sql:%v,args:%v, result is success", reflect.TypeOf(v), sql, jsonString(c.redactArgs(v, args)))
 	}
 	return
 }
 
 func ApplyUnify(queryer interface{}, ctx context.Context, v interface{}, enabled ...string) (err error) {
+	defer Default.recoverError(&err)
 	err = Default.applyUnify(1, queryer, ctx, v, enabled...)
 	return
 }
 
 func (c *CRUD) ApplyUnify(queryer interface{}, ctx context.Context, v interface{}, enabled ...string) (err error) {
+	defer c.recoverError(&err)
 	err = c.applyUnify(1, queryer, ctx, v, enabled...)
 	return
 }
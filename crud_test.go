@@ -2,6 +2,7 @@ package crud
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -60,6 +61,160 @@ func TestBuildOrderby(t *testing.T) {
 	}
 }
 
+func TestParseOrderby(t *testing.T) {
+	if v, err := ParseOrderby("tid,update_time", "-update_time, +tid"); err != nil || v != "order by update_time desc,tid asc" {
+		t.Error(err, v)
+		return
+	}
+	if v, err := ParseOrderby("tid,update_time", "tid"); err != nil || v != "order by tid" {
+		t.Error(err, v)
+		return
+	}
+	for _, order := range []string{"-x", "tid,", "+", "tid desc", "tid;drop table crud_object"} {
+		if _, err := ParseOrderby("tid,update_time", order); err == nil {
+			t.Error(order)
+			return
+		}
+	}
+	search := &struct {
+		Model CrudObject `json:"model"`
+		Page  struct {
+			Order string `json:"order" default:"order by tid desc" orderby:"tid,update_time,create_time"`
+			Limit int    `json:"limit"`
+		} `json:"page"`
+		Query struct {
+			Objects []*CrudObject `json:"objects"`
+		} `json:"query" filter:"tid#all"`
+	}{}
+	search.Page.Limit = 10
+	if sql, _ := QueryUnifySQL(search, "Query"); sql != "select tid from crud_object  order by tid desc limit 10 offset 0" {
+		t.Error(sql)
+		return
+	}
+	search.Page.Order = "-update_time,+tid"
+	if sql, _ := QueryUnifySQL(search, "Query"); sql != "select tid from crud_object  order by update_time desc,tid asc limit 10 offset 0" {
+		t.Error(sql)
+		return
+	}
+	search.Page.Order = "order by tid desc"
+	if sql, _ := QueryUnifySQL(search, "Query"); sql != "select tid from crud_object  order by tid desc limit 10 offset 0" {
+		t.Error(sql)
+		return
+	}
+	search.Page.Order = "tid;delete from crud_object"
+	if sql, err := JoinPageUnifyErr("select tid from crud_object", search); err == nil || sql != "select tid from crud_object" {
+		t.Error(err, sql)
+		return
+	}
+	queryer := &eventQueryer{}
+	if err := QueryUnify(queryer, context.Background(), search); err == nil || len(queryer.SQL) > 0 {
+		t.Error(err)
+		return
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("not panic")
+			}
+		}()
+		JoinPageUnify("select tid from crud_object", search)
+	}()
+	{ //model columns
+		search := &SearchCrudObjectUnifySkip{}
+		search.Page.Order = "-update_time,+tid"
+		if sql, err := JoinPageUnifyErr("select tid from crud_object", search); err != nil || sql != "select tid from crud_object order by update_time desc,tid asc" {
+			t.Error(err, sql)
+			return
+		}
+		for _, order := range []string{"order by tid asc", "tid;delete from crud_object", "-xxx"} {
+			search.Page.Order = order
+			if sql, err := JoinPageUnifyErr("select tid from crud_object", search); err == nil || sql != "select tid from crud_object" {
+				t.Error(order, err, sql)
+				return
+			}
+		}
+	}
+	{ //model columns with quote ident
+		quote := NewCRUD(DialectPostgres)
+		quote.QuoteIdent = true
+		search := &SearchCrudObjectUnifySkip{}
+		search.Page.Order = "-update_time,+tid"
+		if sql, err := quote.JoinPageUnifyErr("select tid from crud_object", search); err != nil || sql != `select tid from crud_object order by "update_time" desc,"tid" asc` {
+			t.Error(err, sql)
+			return
+		}
+		search.Page.Order = `-"update_time"`
+		if sql, err := quote.JoinPageUnifyErr("select tid from crud_object", search); err == nil || sql != "select tid from crud_object" {
+			t.Error(err, sql)
+			return
+		}
+	}
+}
+
+func TestPageLimitUnify(t *testing.T) {
+	search := &struct {
+		Model CrudObject `json:"model"`
+		Page  struct {
+			Order  string `json:"order" default:"order by tid desc"`
+			Offset int    `json:"offset" max:"1000"`
+			Limit  int    `json:"limit" default:"20" max:"200"`
+		} `json:"page"`
+		Query struct {
+			Objects []*CrudObject `json:"objects"`
+		} `json:"query" filter:"tid#all"`
+	}{}
+	if sql, _ := QueryUnifySQL(search, "Query"); sql != "select tid from crud_object  order by tid desc limit 20 offset 0" {
+		t.Error(sql)
+		return
+	}
+	search.Page.Limit = 1000000
+	if sql, _ := QueryUnifySQL(search, "Query"); sql != "select tid from crud_object  order by tid desc limit 200 offset 0" {
+		t.Error(sql)
+		return
+	}
+	search.Page.Limit = 50
+	search.Page.Offset = 1000
+	if sql, _ := QueryUnifySQL(search, "Query"); sql != "select tid from crud_object  order by tid desc limit 50 offset 1000" {
+		t.Error(sql)
+		return
+	}
+	search.Page.Offset = 1001
+	queryer := &eventQueryer{}
+	err := QueryUnify(queryer, context.Background(), search)
+	var pageErr *PageError
+	if !errors.As(err, &pageErr) || pageErr.Field != "Offset" || pageErr.Value != 1001 || pageErr.Max != 1000 || len(queryer.SQL) > 0 {
+		t.Error(err)
+		return
+	}
+	if sql, err := JoinPageUnifyErr("select tid from crud_object", search); !errors.As(err, &pageErr) || sql != "select tid from crud_object" {
+		t.Error(err, sql)
+		return
+	}
+	search.Page.Order = "tid;delete from crud_object"
+	if _, err := JoinPageUnifyErr("select tid from crud_object", search); err == nil || errors.As(err, &pageErr) {
+		t.Error(err)
+		return
+	}
+	search.Page.Order = ""
+	{ //crud max
+		search := &SearchCrudObjectUnifySkip{}
+		c := NewCRUD(DialectPostgres)
+		c.MaxLimit = 100
+		c.MaxOffset = 500
+		if sql := c.JoinPageUnify("select tid from crud_object", search); sql != "select tid from crud_object order by tid desc limit 100 offset 0" {
+			t.Error(sql)
+			return
+		}
+		search.Page.Skip = 501
+		search.Page.Limit = 10
+		err = c.QueryUnify(queryer, context.Background(), search)
+		if !errors.As(err, &pageErr) || pageErr.Field != "Skip" || pageErr.Max != 500 {
+			t.Error(err)
+			return
+		}
+	}
+}
+
 func TestQueryCall(t *testing.T) {
 	clearPG()
 	testQueryCall(t, getPG())
@@ -69,21 +224,21 @@ func testQueryCall(t *testing.T, queryer Queryer) {
 	var err error
 	var rows Rows
 
-	rows, err = Default.queryerQuery(queryer, context.Background(), "select 1", []interface{}{})
+	rows, err = Default.queryerQuery(queryer, context.Background(), OpQuery, nil, "select 1", []interface{}{})
 	if err != nil {
 		t.Error(err)
 		return
 	}
 	rows.Scan(converter.IntPtr(0))
 	rows.Close()
-	rows, err = Default.queryerQuery(&TestCrudQueryer{Queryer: queryer}, context.Background(), "select 1", []interface{}{})
+	rows, err = Default.queryerQuery(&TestCrudQueryer{Queryer: queryer}, context.Background(), OpQuery, nil, "select 1", []interface{}{})
 	if err != nil {
 		t.Error(err)
 		return
 	}
 	rows.Scan(converter.IntPtr(0))
 	rows.Close()
-	rows, err = Default.queryerQuery(func() interface{} { return queryer }, context.Background(), "select 1", []interface{}{})
+	rows, err = Default.queryerQuery(func() interface{} { return queryer }, context.Background(), OpQuery, nil, "select 1", []interface{}{})
 	if err != nil {
 		t.Error(err)
 		return
@@ -94,20 +249,20 @@ func testQueryCall(t *testing.T, queryer Queryer) {
 		defer func() {
 			recover()
 		}()
-		Default.queryerQuery("xxx", context.Background(), "select 1", []interface{}{})
+		Default.queryerQuery("xxx", context.Background(), OpQuery, nil, "select 1", []interface{}{})
 	}()
 
-	err = Default.queryerQueryRow(queryer, context.Background(), "select 1", []interface{}{}).Scan(converter.IntPtr(0))
+	err = Default.queryerQueryRow(queryer, context.Background(), OpQuery, nil, "select 1", []interface{}{}).Scan(converter.IntPtr(0))
 	if err != nil {
 		t.Error(err)
 		return
 	}
-	err = Default.queryerQueryRow(&TestCrudQueryer{Queryer: queryer}, context.Background(), "select 1", []interface{}{}).Scan(converter.IntPtr(0))
+	err = Default.queryerQueryRow(&TestCrudQueryer{Queryer: queryer}, context.Background(), OpQuery, nil, "select 1", []interface{}{}).Scan(converter.IntPtr(0))
 	if err != nil {
 		t.Error(err)
 		return
 	}
-	err = Default.queryerQueryRow(func() interface{} { return queryer }, context.Background(), "select 1", []interface{}{}).Scan(converter.IntPtr(0))
+	err = Default.queryerQueryRow(func() interface{} { return queryer }, context.Background(), OpQuery, nil, "select 1", []interface{}{}).Scan(converter.IntPtr(0))
 	if err != nil {
 		t.Error(err)
 		return
@@ -116,20 +271,20 @@ func testQueryCall(t *testing.T, queryer Queryer) {
 		defer func() {
 			recover()
 		}()
-		Default.queryerQueryRow("xxx", context.Background(), "select 1", []interface{}{})
+		Default.queryerQueryRow("xxx", context.Background(), OpQuery, nil, "select 1", []interface{}{})
 	}()
 
-	_, _, err = Default.queryerExec(queryer, context.Background(), "select 1", []interface{}{})
+	_, _, err = Default.queryerExec(queryer, context.Background(), OpQuery, nil, "select 1", []interface{}{})
 	if err != nil {
 		t.Error(err)
 		return
 	}
-	_, _, err = Default.queryerExec(&TestCrudQueryer{Queryer: queryer}, context.Background(), "select 1", []interface{}{})
+	_, _, err = Default.queryerExec(&TestCrudQueryer{Queryer: queryer}, context.Background(), OpQuery, nil, "select 1", []interface{}{})
 	if err != nil {
 		t.Error(err)
 		return
 	}
-	_, _, err = Default.queryerExec(func() interface{} { return queryer }, context.Background(), "select 1", []interface{}{})
+	_, _, err = Default.queryerExec(func() interface{} { return queryer }, context.Background(), OpQuery, nil, "select 1", []interface{}{})
 	if err != nil {
 		t.Error(err)
 		return
@@ -138,7 +293,7 @@ func testQueryCall(t *testing.T, queryer Queryer) {
 		defer func() {
 			recover()
 		}()
-		Default.queryerExec("xxx", context.Background(), "select 1", []interface{}{})
+		Default.queryerExec("xxx", context.Background(), OpQuery, nil, "select 1", []interface{}{})
 	}()
 }
 
@@ -693,6 +848,200 @@ func testInsert(t *testing.T, queryer Queryer) {
 	}
 }
 
+func TestInsertBatch(t *testing.T) {
+	clearPG()
+	testInsertBatch(t, getPG())
+}
+
+func testInsertBatch(t *testing.T, queryer Queryer) {
+	var err error
+	{
+		objects := []*CrudObject{}
+		for i := 0; i < 5; i++ {
+			objects = append(objects, newTestObject())
+		}
+		affected, err := InsertBatch(queryer, context.Background(), objects, "^tid#all", 2, "tid#all")
+		if err != nil || affected != 5 {
+			t.Error(err, affected)
+			return
+		}
+		for i, object := range objects {
+			if object.TID < 1 || (i > 0 && object.TID <= objects[i-1].TID) {
+				t.Error("error")
+				return
+			}
+		}
+	}
+	{
+		objects := []CrudObject{*newTestObject(), *newTestObject(), *newTestObject()}
+		affected, err := Default.InsertBatch(queryer, context.Background(), &objects, "^tid#all", 0, "tid#all")
+		if err != nil || affected != 3 || objects[0].TID < 1 || objects[2].TID < 1 {
+			t.Error(err, affected)
+			return
+		}
+		affected, err = InsertBatch(queryer, context.Background(), objects, "^tid#all", 0, "")
+		if err != nil || affected != 3 {
+			t.Error(err, affected)
+			return
+		}
+	}
+	{
+		crud := *Default
+		crud.ArgLimit = 50
+		objects := []*CrudObject{}
+		for i := 0; i < 10; i++ {
+			objects = append(objects, newTestObject())
+		}
+		affected, err := crud.InsertBatch(queryer, context.Background(), objects, "^tid#all", 0, "tid#all")
+		if err != nil || affected != 10 || objects[9].TID < 1 {
+			t.Error(err, affected)
+			return
+		}
+	}
+	{
+		affected, err := InsertBatch(queryer, context.Background(), []*CrudObject{}, "^tid#all", 0, "")
+		if err != nil || affected != 0 {
+			t.Error(err, affected)
+			return
+		}
+	}
+	{ //error
+		_, err = InsertBatch(queryer, context.Background(), newTestObject(), "^tid#all", 0, "")
+		if err == nil {
+			t.Error(err)
+			return
+		}
+		image := "image"
+		objects := []*CrudObject{newTestObject(), newTestObject()}
+		objects[0].Image = nil
+		objects[1].Image = &image
+		_, err = InsertBatch(queryer, context.Background(), objects, "^tid", 0, "")
+		if err == nil {
+			t.Error(err)
+			return
+		}
+		_, err = InsertBatch(queryer, context.Background(), []*CrudObject{newTestObject()}, "^tid#all", 0, "xxx#all")
+		if err == nil {
+			t.Error(err)
+			return
+		}
+		_, err = InsertBatch(queryer, context.Background(), []*CrudObject{newTestObject()}, "xxx#all", 0, "")
+		if err == nil {
+			t.Error(err)
+			return
+		}
+	}
+}
+
+func TestUpsertSQL(t *testing.T) {
+	object := &CrudObject{TID: 100, Title: "title", Level: 1}
+	sql, args := UpsertSQL(object, "tid,title,level", "tid", "title,level")
+	if sql != "insert into crud_object(tid,level,title) values($1,$2,$3) on conflict (tid) do update set level=excluded.level,title=excluded.title" || len(args) != 3 {
+		t.Error(sql, args)
+		return
+	}
+	sql, args = Default.UpsertSQL(object, "tid,title,level", "tid", "", "returning tid")
+	if sql != "insert into crud_object(tid,level,title) values($1,$2,$3) on conflict (tid) do nothing returning tid" || len(args) != 3 {
+		t.Error(sql, args)
+		return
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	clearPG()
+	testUpsert(t, getPG())
+}
+
+type noRowsQueryer struct {
+	eventQueryer
+	Err error
+}
+
+func (n *noRowsQueryer) QueryRow(ctx context.Context, query string, args ...interface{}) (row Row) {
+	n.SQL = append(n.SQL, query)
+	row = errorRow{err: n.Err}
+	return
+}
+
+func TestUpsertSkipped(t *testing.T) {
+	object := &CrudObject{TID: 1, Title: "abc"}
+	queryer := &noRowsQueryer{Err: ErrNoRows}
+	insertId, err := UpsertFilter(queryer, context.Background(), object, "tid,title", "tid", "", "tid#all")
+	if err != nil || insertId != 0 || queryer.SQL[0] != "insert into crud_object(tid,title) values($1,$2) on conflict (tid) do nothing returning tid" {
+		t.Error(err, insertId, queryer.SQL)
+		return
+	}
+	c := NewCRUD(DialectPostgres)
+	c.ErrNoRows = fmt.Errorf("no rows")
+	queryer.Err = c.ErrNoRows
+	_, err = c.UpsertFilter(queryer, WithTenant(context.Background(), 100), &TenantCrudObject{TID: 1, Title: "abc"}, "tid,user_id,title", "tid", "title", "tid#all")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	queryer.Err = fmt.Errorf("other")
+	_, err = c.UpsertFilter(queryer, context.Background(), object, "tid,title", "tid", "", "tid#all")
+	if err == nil {
+		t.Error(err)
+		return
+	}
+}
+
+func testUpsert(t *testing.T, queryer Queryer) {
+	var err error
+	object := newTestObject()
+	_, err = InsertFilter(queryer, context.Background(), object, "^tid#all", "returning", "tid#all")
+	if err != nil || object.TID < 1 {
+		t.Error(err)
+		return
+	}
+	{
+		upsert := newTestObject()
+		upsert.TID = object.TID
+		upsert.Title = "upsert"
+		upsert.Level = 10
+		_, err = UpsertFilter(queryer, context.Background(), upsert, "#all", "tid", "title,level", "tid,title,level#all")
+		if err != nil || upsert.TID != object.TID || upsert.Title != "upsert" || upsert.Level != 10 {
+			t.Error(err)
+			return
+		}
+		var title string
+		err = QueryRow(queryer, context.Background(), &CrudObject{}, "title#all", "select title from crud_object where tid=$1", []interface{}{object.TID}, &title, "title")
+		if err != nil || title != "upsert" {
+			t.Error(err, title)
+			return
+		}
+	}
+	{
+		upsert := newTestObject()
+		upsert.TID = object.TID
+		upsert.Title = "ignore"
+		_, err = Default.UpsertFilter(queryer, context.Background(), upsert, "#all", "tid", "", "")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		insertId, err := UpsertFilter(queryer, context.Background(), upsert, "#all", "tid", "", "title#all")
+		if err != nil || insertId != 0 || upsert.Title != "ignore" {
+			t.Error(err, insertId, upsert.Title)
+			return
+		}
+	}
+	{ //error
+		upsert := newTestObject()
+		_, err = UpsertFilter(queryer, context.Background(), upsert, "#all", "xxx", "title", "")
+		if err == nil {
+			t.Error(err)
+			return
+		}
+		_, err = UpsertFilter(queryer, context.Background(), upsert, "#all", "xxx", "title", "tid#all")
+		if err == nil {
+			t.Error(err)
+			return
+		}
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	clearPG()
 	testUpdate(t, getPG())
@@ -954,6 +1303,179 @@ func testUpdate(t *testing.T, queryer Queryer) {
 	}
 }
 
+type DeleteCrudObjectUnify struct {
+	Model CrudObject `json:"model"`
+	Where struct {
+		TID    int64                 `json:"tid"`
+		Status CrudObjectStatusArray `json:"status" cmp:"status=any($%v)"`
+	} `json:"where" join:"and"`
+}
+
+func TestDeleteSQL(t *testing.T) {
+	if sql := DeleteSQL(&CrudObject{}); sql != "delete from crud_object" {
+		t.Error(sql)
+		return
+	}
+	if sql := Default.DeleteSQL(&CrudObject{}, "where tid=$1"); sql != "delete from crud_object where tid=$1" {
+		t.Error(sql)
+		return
+	}
+	unify := &DeleteCrudObjectUnify{}
+	unify.Where.TID = 100
+	unify.Where.Status = CrudObjectStatusShow
+	sql, args := DeleteUnifySQL(unify)
+	if sql != "delete from crud_object where tid = $1  and status=any($2)" || len(args) != 2 {
+		t.Error(sql, args)
+		return
+	}
+	sql, args = Default.DeleteUnifySQL(unify)
+	if sql != "delete from crud_object where tid = $1  and status=any($2)" || len(args) != 2 {
+		t.Error(sql, args)
+		return
+	}
+}
+
+func TestDelete(t *testing.T) {
+	clearPG()
+	testDelete(t, getPG())
+}
+
+func testDelete(t *testing.T, queryer Queryer) {
+	var err error
+	addObject := func() (object *CrudObject) {
+		object = newTestObject()
+		_, err = InsertFilter(queryer, context.Background(), object, "^tid#all", "returning", "tid#all")
+		if err != nil || object.TID < 1 {
+			panic(err)
+		}
+		return
+	}
+	{
+		object := addObject()
+		where, args := AppendWhere(nil, nil, true, "tid=$%v", object.TID)
+		affected, err := Delete(queryer, context.Background(), object, DeleteSQL(object), where, "and", args)
+		if err != nil || affected != 1 {
+			t.Error(err)
+			return
+		}
+		object = addObject()
+		where, args = AppendWhere(nil, nil, true, "tid=$%v", object.TID)
+		err = DeleteRow(queryer, context.Background(), object, DeleteSQL(object), where, "and", args)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = Default.DeleteRow(queryer, context.Background(), object, Default.DeleteSQL(object), where, "and", args)
+		if err != ErrNoRows {
+			t.Error(err)
+			return
+		}
+		_, err = Default.Delete(queryer, context.Background(), object, DeleteSQL(object), where, "and", args)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	{
+		object := addObject()
+		affected, err := DeleteFilter(queryer, context.Background(), object, "tid", nil, "and", nil)
+		if err != nil || affected != 1 {
+			t.Error(err)
+			return
+		}
+		object = addObject()
+		where, args := AppendWhere(nil, nil, true, "status=$%v", object.Status)
+		err = DeleteRowFilter(queryer, context.Background(), object, "tid", where, "and", args)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = Default.DeleteRowFilter(queryer, context.Background(), object, "tid", where, "and", args)
+		if err != ErrNoRows {
+			t.Error(err)
+			return
+		}
+		_, err = Default.DeleteFilter(queryer, context.Background(), object, "tid", nil, "and", nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	{
+		object := addObject()
+		affected, err := DeleteWheref(queryer, context.Background(), object, "tid=$%v", object.TID)
+		if err != nil || affected != 1 {
+			t.Error(err)
+			return
+		}
+		object = addObject()
+		err = DeleteRowWheref(queryer, context.Background(), object, "tid=$%v", object.TID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = Default.DeleteRowWheref(queryer, context.Background(), object, "tid=$%v", object.TID)
+		if err != ErrNoRows {
+			t.Error(err)
+			return
+		}
+		_, err = Default.DeleteWheref(queryer, context.Background(), object, "tid=$%v", object.TID)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	{
+		object := addObject()
+		unify := &DeleteCrudObjectUnify{}
+		unify.Where.TID = object.TID
+		unify.Where.Status = CrudObjectStatusShow
+		affected, err := DeleteUnify(queryer, context.Background(), unify)
+		if err != nil || affected != 1 {
+			t.Error(err)
+			return
+		}
+		affected, err = Default.DeleteUnify(queryer, context.Background(), unify)
+		if err != nil || affected != 0 {
+			t.Error(err)
+			return
+		}
+	}
+	{ //error
+		object := newTestObject()
+		_, err = Delete(queryer, context.Background(), object, "xx", nil, "", nil)
+		if err == nil {
+			t.Error(err)
+			return
+		}
+		err = DeleteRow(queryer, context.Background(), object, "xx", nil, "", nil)
+		if err == nil {
+			t.Error(err)
+			return
+		}
+		_, err = DeleteFilter(queryer, context.Background(), object, "tid", []string{"xxx=$1"}, "and", []interface{}{1})
+		if err == nil {
+			t.Error(err)
+			return
+		}
+		err = DeleteRowFilter(queryer, context.Background(), object, "tid", []string{"xxx=$1"}, "and", []interface{}{1})
+		if err == nil {
+			t.Error(err)
+			return
+		}
+		_, err = DeleteWheref(queryer, context.Background(), object, "xxx=$%v", 1)
+		if err == nil {
+			t.Error(err)
+			return
+		}
+		err = DeleteRowWheref(queryer, context.Background(), object, "xxx=$%v", 1)
+		if err == nil {
+			t.Error(err)
+			return
+		}
+	}
+}
+
 func TestJoinWhere(t *testing.T) {
 	clearPG()
 	testJoinWhere(t, getPG())
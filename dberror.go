@@ -0,0 +1,120 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DBErrorKind is the kind of database error
+type DBErrorKind int
+
+const (
+	UnknownError DBErrorKind = iota
+	UniqueViolation
+	ForeignKeyViolation
+	NotNullViolation
+	CheckViolation
+	Deadlock
+	SerializationFailure
+	Timeout
+)
+
+func (k DBErrorKind) String() string {
+	switch k {
+	case UniqueViolation:
+		return "UniqueViolation"
+	case ForeignKeyViolation:
+		return "ForeignKeyViolation"
+	case NotNullViolation:
+		return "NotNullViolation"
+	case CheckViolation:
+		return "CheckViolation"
+	case Deadlock:
+		return "Deadlock"
+	case SerializationFailure:
+		return "SerializationFailure"
+	case Timeout:
+		return "Timeout"
+	default:
+		return "UnknownError"
+	}
+}
+
+// DBError is the classified database error, the driver error is kept in Err
+type DBError struct {
+	Kind       DBErrorKind
+	Constraint string
+	Table      string
+	Column     string
+	Err        error
+}
+
+func (d *DBError) Error() string {
+	return fmt.Sprintf("%v(constraint:%v,table:%v,column:%v) by %v", d.Kind, d.Constraint, d.Table, d.Column, d.Err)
+}
+
+func (d *DBError) Unwrap() error {
+	return d.Err
+}
+
+// ErrorClassifier will classify driver error to DBError, nil is returned when err is not supported
+type ErrorClassifier func(err error) *DBError
+
+var classifierLock = sync.RWMutex{}
+var classifierAll = []ErrorClassifier{}
+
+// RegisterClassifier will register driver classifier, it is called by pgx/sqlx adapter on init
+func RegisterClassifier(classifier ErrorClassifier) {
+	classifierLock.Lock()
+	defer classifierLock.Unlock()
+	classifierAll = append(classifierAll, classifier)
+}
+
+// ClassifyError will classify err by registered classifier, nil is returned when err is nil,
+// Kind is UnknownError when err is not supported by any classifier
+func ClassifyError(err error) (dbErr *DBError) {
+	if err == nil {
+		return
+	}
+	if errors.As(err, &dbErr) {
+		return
+	}
+	classifierLock.RLock()
+	classifiers := classifierAll
+	classifierLock.RUnlock()
+	for _, classifier := range classifiers {
+		if dbErr = classifier(err); dbErr != nil {
+			return
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		dbErr = &DBError{Kind: Timeout, Err: err}
+		return
+	}
+	dbErr = &DBError{Kind: UnknownError, Err: err}
+	return
+}
+
+// SQLStateKind will return the DBErrorKind by SQLSTATE code, it is used by postgres driver classifier
+func SQLStateKind(code string) DBErrorKind {
+	switch code {
+	case "23505":
+		return UniqueViolation
+	case "23503":
+		return ForeignKeyViolation
+	case "23502":
+		return NotNullViolation
+	case "23514":
+		return CheckViolation
+	case "40P01":
+		return Deadlock
+	case "40001":
+		return SerializationFailure
+	case "57014", "55P03":
+		return Timeout
+	default:
+		return UnknownError
+	}
+}
@@ -0,0 +1,54 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type classifyTestError struct {
+	Code string
+}
+
+func (c *classifyTestError) Error() string { return c.Code }
+
+func TestClassifyError(t *testing.T) {
+	RegisterClassifier(func(err error) *DBError {
+		var testErr *classifyTestError
+		if errors.As(err, &testErr) {
+			return &DBError{Kind: SQLStateKind(testErr.Code), Constraint: "crud_object_pkey", Err: err}
+		}
+		return nil
+	})
+	if ClassifyError(nil) != nil {
+		t.Error("error")
+		return
+	}
+	dbErr := ClassifyError(fmt.Errorf("insert fail:%w", &classifyTestError{Code: "23505"}))
+	if dbErr.Kind != UniqueViolation || dbErr.Constraint != "crud_object_pkey" || !errors.As(dbErr, new(*classifyTestError)) {
+		t.Error(dbErr)
+		return
+	}
+	if again := ClassifyError(fmt.Errorf("wrap:%w", dbErr)); again != dbErr {
+		t.Error(again)
+		return
+	}
+	if dbErr = ClassifyError(fmt.Errorf("query:%w", context.DeadlineExceeded)); dbErr.Kind != Timeout {
+		t.Error(dbErr)
+		return
+	}
+	if dbErr = ClassifyError(fmt.Errorf("xxx")); dbErr.Kind != UnknownError || dbErr.Error() != "UnknownError(constraint:,table:,column:) by xxx" {
+		t.Error(dbErr)
+		return
+	}
+	for code, kind := range map[string]DBErrorKind{
+		"23505": UniqueViolation, "23503": ForeignKeyViolation, "23502": NotNullViolation, "23514": CheckViolation,
+		"40P01": Deadlock, "40001": SerializationFailure, "57014": Timeout, "42P01": UnknownError,
+	} {
+		if SQLStateKind(code) != kind || len(kind.String()) < 1 {
+			t.Error(code, kind)
+			return
+		}
+	}
+}
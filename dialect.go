@@ -0,0 +1,142 @@
+package crud
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/codingeasygo/util/xsql"
+)
+
+// Dialect is the database specific sql render used by CRUD
+type Dialect interface {
+	//Name is the dialect name, like postgres/sqlite/mysql
+	Name() string
+	//Placeholder will return the bind parameter on index, index is start by 1
+	Placeholder(index int) string
+	//Quote will quote the identifier
+	Quote(name string) string
+	//Page will return the limit/offset clause
+	Page(offset, limit int) string
+	//Returning is true when database support insert/update ... returning
+	Returning() bool
+	//RowLock will return the row lock clause used by select
+	RowLock() string
+	//ParmConv will convert the parameter, like array to database array
+	ParmConv(on, fieldName, fieldFunc string, field reflect.StructField, value interface{}) interface{}
+	//Upsert will return the conflict clause append to insert, it should do nothing when updates is empty
+	Upsert(conflict string, updates []string) string
+}
+
+var (
+	DialectPostgres Dialect = PostgresDialect{}
+	DialectSQLite   Dialect = SQLiteDialect{}
+	DialectMySQL    Dialect = MySQLDialect{}
+)
+
+type PostgresDialect struct{}
+
+func (PostgresDialect) Name() string { return "postgres" }
+
+func (PostgresDialect) Placeholder(index int) string { return fmt.Sprintf("$%d", index) }
+
+func (PostgresDialect) Quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (PostgresDialect) Page(offset, limit int) string {
+	return fmt.Sprintf("limit %v offset %v", limit, offset)
+}
+
+func (PostgresDialect) Returning() bool { return true }
+
+func (PostgresDialect) RowLock() string { return "for update" }
+
+func (PostgresDialect) ParmConv(on, fieldName, fieldFunc string, field reflect.StructField, value interface{}) interface{} {
+	if c, ok := value.(xsql.ArrayConverter); on == "where" && ok {
+		return c.DbArray()
+	}
+	return value
+}
+
+func (PostgresDialect) Upsert(conflict string, updates []string) string {
+	return upsertExcluded(conflict, updates)
+}
+
+type SQLiteDialect struct{}
+
+func (SQLiteDialect) Name() string { return "sqlite" }
+
+func (SQLiteDialect) Placeholder(index int) string { return fmt.Sprintf("$%d", index) }
+
+func (SQLiteDialect) Quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (SQLiteDialect) Page(offset, limit int) string {
+	return fmt.Sprintf("limit %v offset %v", limit, offset)
+}
+
+func (SQLiteDialect) Returning() bool { return true }
+
+func (SQLiteDialect) RowLock() string { return "" }
+
+func (SQLiteDialect) ParmConv(on, fieldName, fieldFunc string, field reflect.StructField, value interface{}) interface{} {
+	if c, ok := value.(xsql.ArrayConverter); on == "where" && ok {
+		return c.InArray()
+	}
+	return value
+}
+
+func (SQLiteDialect) Upsert(conflict string, updates []string) string {
+	return upsertExcluded(conflict, updates)
+}
+
+type MySQLDialect struct{}
+
+func (MySQLDialect) Name() string { return "mysql" }
+
+func (MySQLDialect) Placeholder(index int) string { return "?" }
+
+func (MySQLDialect) Quote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (MySQLDialect) Page(offset, limit int) string {
+	return fmt.Sprintf("limit %v offset %v", limit, offset)
+}
+
+func (MySQLDialect) Returning() bool { return false }
+
+func (MySQLDialect) RowLock() string { return "for update" }
+
+func (MySQLDialect) ParmConv(on, fieldName, fieldFunc string, field reflect.StructField, value interface{}) interface{} {
+	if c, ok := value.(xsql.ArrayConverter); on == "where" && ok {
+		return c.InArray()
+	}
+	return value
+}
+
+func (MySQLDialect) Upsert(conflict string, updates []string) string {
+	sets := []string{}
+	for _, update := range updates {
+		sets = append(sets, fmt.Sprintf("%v=values(%v)", update, update))
+	}
+	if len(sets) < 1 {
+		//mysql is not supported do nothing, so update the conflict field to itself
+		conflictField := strings.TrimSpace(strings.Split(conflict, ",")[0])
+		sets = append(sets, fmt.Sprintf("%v=%v", conflictField, conflictField))
+	}
+	return "on duplicate key update " + strings.Join(sets, ",")
+}
+
+func upsertExcluded(conflict string, updates []string) string {
+	sets := []string{}
+	for _, update := range updates {
+		sets = append(sets, fmt.Sprintf("%v=excluded.%v", update, update))
+	}
+	if len(sets) < 1 {
+		return fmt.Sprintf("on conflict (%v) do nothing", conflict)
+	}
+	return fmt.Sprintf("on conflict (%v) do update set %v", conflict, strings.Join(sets, ","))
+}
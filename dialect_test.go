@@ -0,0 +1,169 @@
+package crud
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/codingeasygo/util/xsql"
+)
+
+func TestDialect(t *testing.T) {
+	for _, dialect := range []Dialect{DialectPostgres, DialectSQLite, DialectMySQL} {
+		if len(dialect.Name()) < 1 || len(dialect.Placeholder(1)) < 1 || len(dialect.Quote("type")) < 1 || len(dialect.Page(0, 10)) < 1 {
+			t.Error("error")
+			return
+		}
+		dialect.Returning()
+		dialect.RowLock()
+		if v := dialect.ParmConv("where", "int_array", "", reflect.StructField{}, xsql.IntArray{1, 2}); reflect.DeepEqual(v, xsql.IntArray{1, 2}) {
+			t.Error("error")
+			return
+		}
+		if v := dialect.ParmConv("insert", "int_array", "", reflect.StructField{}, xsql.IntArray{1, 2}); !reflect.DeepEqual(v, xsql.IntArray{1, 2}) {
+			t.Error("error")
+			return
+		}
+	}
+	if DialectPostgres.Quote(`a"b`) != `"a""b"` || DialectMySQL.Quote("a`b") != "`a``b`" {
+		t.Error("error")
+		return
+	}
+}
+
+func TestDialectSQL(t *testing.T) {
+	mysql := NewCRUD(DialectMySQL)
+	object := &CrudObject{TID: 100, Title: "title", Level: 1}
+	sql, args := mysql.InsertSQL(object, "tid,title,level")
+	if sql != "insert into crud_object(tid,level,title) values(?,?,?) " || len(args) != 3 {
+		t.Error(sql, args)
+		return
+	}
+	sql, args = mysql.UpdateSQL(object, "title,level", nil, "where tid=?")
+	if sql != "update crud_object set level=?,title=? where tid=?" || len(args) != 2 {
+		t.Error(sql, args)
+		return
+	}
+	where, args := mysql.AppendWheref(nil, nil, "tid=$%v,status=any($%v)", 100, xsql.IntArray{1})
+	if len(where) != 2 || where[0] != "tid=?" || where[1] != "status=any(?)" || args[1] != "1" {
+		t.Error(where, args)
+		return
+	}
+	where, args = mysql.AppendWheref(nil, nil, "tid=$%v,title like $%v or data::text like $%v", 100, "a%")
+	if len(where) != 2 || where[1] != "title like ? or data::text like ?" || len(args) != 3 || args[1] != "a%" || args[2] != "a%" {
+		t.Error(where, args)
+		return
+	}
+	where, args = mysql.FilterWhere(nil, &struct {
+		Title string `json:"title" cmp:"(title like $%v or image like $%v)"`
+	}{Title: "a%"}, "title")
+	if len(where) != 1 || len(args) != 2 {
+		t.Error(where, args)
+		return
+	}
+	sql = mysql.JoinPage("select * from crud_object", "order by tid", 10, 20)
+	if sql != "select * from crud_object order by tid limit 20 offset 10" {
+		t.Error(sql)
+		return
+	}
+	sqlite := NewCRUD(DialectSQLite)
+	where, args = sqlite.AppendWheref(nil, nil, "tid=$%v,tid=$%v or tid=$%v", 100, 200)
+	if len(where) != 2 || where[0] != "tid=$1" || where[1] != "tid=$2 or tid=$2" || args[1] != 200 {
+		t.Error(where, args)
+		return
+	}
+	legacy := *Default
+	legacy.Dialect = nil
+	where, _ = legacy.AppendWheref(nil, nil, "tid=$%v", 100)
+	if len(where) != 1 || where[0] != "tid=$1" {
+		t.Error(where)
+		return
+	}
+}
+
+func TestDialectUpsertSQL(t *testing.T) {
+	mysql := NewCRUD(DialectMySQL)
+	object := &CrudObject{TID: 100, Title: "title", Level: 1}
+	sql, args := mysql.UpsertSQL(object, "tid,title,level", "tid", "title,level")
+	if sql != "insert into crud_object(tid,level,title) values(?,?,?) on duplicate key update level=values(level),title=values(title)" || len(args) != 3 {
+		t.Error(sql, args)
+		return
+	}
+	sql, _ = mysql.UpsertSQL(object, "tid,title,level", "tid", "")
+	if sql != "insert into crud_object(tid,level,title) values(?,?,?) on duplicate key update tid=tid" {
+		t.Error(sql)
+		return
+	}
+	sqlite := NewCRUD(DialectSQLite)
+	sql, _ = sqlite.UpsertSQL(object, "tid,title,level", "tid", "title")
+	if sql != "insert into crud_object(tid,level,title) values($1,$2,$3) on conflict (tid) do update set title=excluded.title" {
+		t.Error(sql)
+		return
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	pg := NewCRUD(DialectPostgres)
+	pg.QuoteIdent = true
+	pg.NameConv = Default.NameConv
+	object := &CrudObject{TID: 100, Type: "a", Title: "title", Level: 1, Data: xsql.M{"a": 1}}
+	if table := pg.Table(object); table != `"crud_object"` {
+		t.Error(table)
+		return
+	}
+	sql, _ := pg.InsertSQL(object, "tid,type,level")
+	if sql != `insert into "crud_object"("tid","type","level") values($1,$2,$3) ` {
+		t.Error(sql)
+		return
+	}
+	sql, _ = pg.UpdateSQL(object, "type,level", nil, "where tid=$3")
+	if sql != `update "crud_object" set "type"=$1,"level"=$2 where tid=$3` {
+		t.Error(sql)
+		return
+	}
+	table, fields := pg.QueryField(object, "o.tid,type,data#all")
+	if table != `"crud_object" o` || len(fields) != 3 || fields[0] != `o."tid"` || fields[1] != `o."type"` || fields[2] != `o."data"::text` {
+		t.Error(table, fields)
+		return
+	}
+	table, fields = pg.QueryField(object, "count(tid)#all")
+	if table != `"crud_object"` || len(fields) != 1 || fields[0] != `count("tid")` {
+		t.Error(table, fields)
+		return
+	}
+	table, fields = pg.QueryField(object, "*|tid#all")
+	if table != `"crud_object"` || len(fields) != 5 || fields[0] != `"tid"` {
+		t.Error(table, fields)
+		return
+	}
+	table, fields = pg.QueryField(MetaWith("public.crud_object", int64(0)), "count(tid)#all")
+	if table != `"public"."crud_object"` || len(fields) != 1 || fields[0] != `count("tid")` {
+		t.Error(table, fields)
+		return
+	}
+	where, args := pg.FilterWhere(nil, object, "type,level")
+	if len(where) != 2 || where[0] != `"type" = $1` || where[1] != `"level" = $2` || len(args) != 2 {
+		t.Error(where, args)
+		return
+	}
+	sql, _ = pg.UpsertSQL(object, "tid,level", "tid", "level")
+	if sql != `insert into "crud_object"("tid","level") values($1,$2) on conflict (tid) do update set "level"=excluded."level"` {
+		t.Error(sql)
+		return
+	}
+	mysql := NewCRUD(DialectMySQL)
+	mysql.QuoteIdent = true
+	sql, _ = mysql.InsertSQL(object, "tid,type,level")
+	if sql != "insert into `crud_object`(`tid`,`type`,`level`) values(?,?,?) " {
+		t.Error(sql)
+		return
+	}
+	sql = mysql.CountSQL(object, "count(*)#all")
+	if sql != "select count(*) from `crud_object`" {
+		t.Error(sql)
+		return
+	}
+	if table = Default.Table(object); table != "crud_object" {
+		t.Error(table)
+		return
+	}
+}
@@ -0,0 +1,116 @@
+package crud
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ErrUnsupportedQueryer is returned when queryer is not Queryer/CrudQueryer or func returning them
+type ErrUnsupportedQueryer struct {
+	Type reflect.Type
+}
+
+func (e *ErrUnsupportedQueryer) Error() string {
+	return fmt.Sprintf("queryer %v is not supported", e.Type)
+}
+
+func (e *ErrUnsupportedQueryer) typedError() {}
+
+// ErrFilterMismatch is returned when the field count of filter/formats is not matched to values, or the filter type is not supported
+type ErrFilterMismatch struct {
+	Filter string
+	Fields int
+	Values int
+	Type   reflect.Type
+}
+
+func (e *ErrFilterMismatch) Error() string {
+	if e.Type != nil {
+		return fmt.Sprintf("filter %v type %v is not supported", e.Filter, e.Type)
+	}
+	return fmt.Sprintf("filter %v having %v fields is not equal to %v values", e.Filter, e.Fields, e.Values)
+}
+
+func (e *ErrFilterMismatch) typedError() {}
+
+// ErrInvalidUnify is returned when the unify struct is not having required field
+type ErrInvalidUnify struct {
+	Type  reflect.Type
+	Field string
+}
+
+func (e *ErrInvalidUnify) Error() string {
+	return fmt.Sprintf("%v is not exits in %v", e.Field, e.Type)
+}
+
+func (e *ErrInvalidUnify) typedError() {}
+
+// ErrUnknownScope is returned when the named scope is not registered on model
+type ErrUnknownScope struct {
+	Type reflect.Type
+	Name string
+}
+
+func (e *ErrUnknownScope) Error() string {
+	return fmt.Sprintf("scope %v is not registered on %v", e.Name, e.Type)
+}
+
+func (e *ErrUnknownScope) typedError() {}
+
+// PageError is returned when the client page value of unify is exceeded the max
+type PageError struct {
+	Field string
+	Value int
+	Max   int
+}
+
+func (p *PageError) Error() string {
+	return fmt.Sprintf("page %v=%v is exceeded max %v", p.Field, p.Value, p.Max)
+}
+
+// typedError is the marker of exported error type, it is recovered from the panic of sql builder by recoverError
+type typedError interface {
+	error
+	typedError()
+}
+
+// fail will panic err on Strict mode, else return err
+func (c *CRUD) fail(err error) error {
+	if c.Strict {
+		panic(err)
+	}
+	return err
+}
+
+// recoverError will recover the typed error panic from sql builder to err, nothing is recovered on Strict mode
+func (c *CRUD) recoverError(err *error) {
+	if c.Strict {
+		return
+	}
+	switch r := recover().(type) {
+	case nil:
+	case typedError:
+		*err = r
+	default:
+		panic(r)
+	}
+}
+
+// unifyCheck will panic ErrInvalidUnify when v is not struct or not having fields
+func (c *CRUD) unifyCheck(v interface{}, fields ...string) {
+	reflectValue := reflect.Indirect(reflect.ValueOf(v))
+	if reflectValue.Kind() != reflect.Struct {
+		panic(&ErrInvalidUnify{Type: reflect.TypeOf(v), Field: "struct"})
+	}
+	for _, field := range fields {
+		if !reflectValue.FieldByName(field).IsValid() {
+			panic(&ErrInvalidUnify{Type: reflectValue.Type(), Field: field})
+		}
+	}
+}
+
+type errorRow struct {
+	err error
+}
+
+func (e errorRow) Scan(dest ...interface{}) error { return e.err }
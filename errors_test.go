@@ -0,0 +1,174 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTypedError(t *testing.T) {
+	ctx := context.Background()
+	queryer := &eventQueryer{}
+	{ //unsupported queryer
+		var queryerErr *ErrUnsupportedQueryer
+		_, err := DeleteWheref("xxx", ctx, &CrudObject{}, "tid=$%v", 1)
+		if !errors.As(err, &queryerErr) {
+			t.Error(err)
+			return
+		}
+		err = QueryWheref("xxx", ctx, &CrudObject{}, "#all", "tid=$%v", []interface{}{1}, "", 0, 0)
+		if !errors.As(err, &queryerErr) {
+			t.Error(err)
+			return
+		}
+		var count int64
+		err = CountWheref(func() interface{} { return "xxx" }, ctx, &CrudObject{}, "count(tid)#all", "tid=$%v", []interface{}{1}, "", &count, "tid")
+		if !errors.As(err, &queryerErr) || queryerErr.Error() != "queryer string is not supported" {
+			t.Error(err)
+			return
+		}
+	}
+	{ //filter mismatch
+		var filterErr *ErrFilterMismatch
+		_, err := DeleteWheref(queryer, ctx, &CrudObject{}, "tid=$%v,title=$%v", 1)
+		if !errors.As(err, &filterErr) || filterErr.Fields != 2 || filterErr.Values != 1 {
+			t.Error(err)
+			return
+		}
+		var count int64
+		err = CountFilter(queryer, ctx, []interface{}{TableName("crud_object"), int64(0), int64(0)}, "count(tid)#all", nil, "", nil, "", &count, "tid")
+		if !errors.As(err, &filterErr) || filterErr.Fields != 1 || filterErr.Values != 2 {
+			t.Error(err)
+			return
+		}
+		repo := NewRepo[CrudObject](nil)
+		_, err = repo.Find(ctx, queryer, "tid=$%v", 1, 2)
+		if !errors.As(err, &filterErr) {
+			t.Error(err)
+			return
+		}
+		if len(queryer.SQL) > 0 {
+			t.Error(queryer.SQL)
+			return
+		}
+	}
+	{ //invalid unify
+		var unifyErr *ErrInvalidUnify
+		search := &SearchCrudObjectUnify{}
+		err := QueryUnifyTarget(queryer, ctx, search, "Abc")
+		if !errors.As(err, &unifyErr) || unifyErr.Field != "Abc" {
+			t.Error(err)
+			return
+		}
+		err = CountUnifyTarget(queryer, ctx, search, "Abc")
+		if !errors.As(err, &unifyErr) {
+			t.Error(err)
+			return
+		}
+		_, err = DeleteUnify(queryer, ctx, &struct{ Where struct{} }{})
+		if !errors.As(err, &unifyErr) || unifyErr.Field != "Model" {
+			t.Error(err)
+			return
+		}
+		err = ScanUnifyTarget(&eventRows{}, search, "Abc")
+		if !errors.As(err, &unifyErr) {
+			t.Error(err)
+			return
+		}
+	}
+	{ //error variant
+		var unifyErr *ErrInvalidUnify
+		var filterErr *ErrFilterMismatch
+		search := &SearchCrudObjectUnify{}
+		if _, _, _, err := ScanUnifyDestErr(search, "Abc"); !errors.As(err, &unifyErr) {
+			t.Error(err)
+			return
+		}
+		if _, _, err := QueryUnifySQLContext(ctx, search, "Abc"); !errors.As(err, &unifyErr) {
+			t.Error(err)
+			return
+		}
+		if _, _, err := AppendWherefErr(nil, nil, "tid=$%v,title=$%v", 1); !errors.As(err, &filterErr) {
+			t.Error(err)
+			return
+		}
+		if _, _, err := JoinWherefErr("select 1", nil, "tid=$%v,title=$%v", 1); !errors.As(err, &filterErr) {
+			t.Error(err)
+			return
+		}
+		if err := FilterFormatCallErr("tid=$%v", nil, func(format string, arg interface{}) {}); !errors.As(err, &filterErr) {
+			t.Error(err)
+			return
+		}
+		if _, err := FilterFieldCallErr("where", []interface{}{1, 2}, "tid", func(fieldName, fieldFunc string, field reflect.StructField, value interface{}) {}); !errors.As(err, &filterErr) {
+			t.Error(err)
+			return
+		}
+		if where, args, err := AppendWherefErr(nil, nil, "tid=$%v", 1); err != nil || len(where) != 1 || len(args) != 1 {
+			t.Error(where, args, err)
+			return
+		}
+		tenant := &TenantCrudObjectUnify{}
+		if _, _, err := QueryUnifySQLContext(ctx, tenant, "Query"); err != ErrTenantMissing {
+			t.Error(err)
+			return
+		}
+		if sql, _, err := CountUnifySQLContext(WithTenant(ctx, 100), tenant); err != nil || sql != "select count(tid) from crud_object where user_id=$1 " {
+			t.Error(sql, err)
+			return
+		}
+		if sql, _, err := DeleteUnifySQLContext(WithTenant(ctx, 100), tenant); err != nil || sql != "delete from crud_object where user_id=$1" {
+			t.Error(sql, err)
+			return
+		}
+		if sql, _, err := JoinWhereUnifyContext(WithScope(ctx, "xxx"), "select 1", nil, search); sql != "" || err == nil {
+			t.Error(sql, err)
+			return
+		}
+		if sql, _ := CountUnifySQL(tenant); sql != "select count(tid) from crud_object " {
+			t.Error(sql)
+			return
+		}
+		if sql, _ := QueryUnifySQL(tenant, "Query"); sql != "select tid,user_id,title,status from crud_object " {
+			t.Error(sql)
+			return
+		}
+		if sql, _ := DeleteUnifySQL(tenant); sql != "delete from crud_object" {
+			t.Error(sql)
+			return
+		}
+		if sql, _ := JoinWhereUnify("select 1", nil, tenant); sql != "select 1" {
+			t.Error(sql)
+			return
+		}
+		err := func() (err error) {
+			defer Default.recoverError(&err)
+			panic(&ErrUnknownScope{Name: "xxx"})
+		}()
+		if _, ok := err.(*ErrUnknownScope); !ok {
+			t.Error(err)
+			return
+		}
+	}
+	{ //strict
+		c := NewCRUD(DialectPostgres)
+		c.Strict = true
+		func() {
+			defer func() {
+				if _, ok := recover().(*ErrFilterMismatch); !ok {
+					t.Error("not panic")
+				}
+			}()
+			c.DeleteWheref(queryer, ctx, &CrudObject{}, "tid=$%v,title=$%v", 1)
+		}()
+		func() {
+			defer func() {
+				if _, ok := recover().(*ErrUnsupportedQueryer); !ok {
+					t.Error("not panic")
+				}
+			}()
+			c.QueryRow("xxx", ctx, &CrudObject{}, "#all", "select 1", nil)
+		}()
+	}
+}
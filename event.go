@@ -0,0 +1,102 @@
+package crud
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+const (
+	OpInsert = "insert"
+	OpUpsert = "upsert"
+	OpUpdate = "update"
+	OpDelete = "delete"
+	OpQuery  = "query"
+	OpCount  = "count"
+	OpUnify  = "unify"
+)
+
+// Event is the info of one sql executing, interceptor can rewrite SQL/Args before calling next,
+// the Duration/InsertId/Affected/Rows/Err is filled after next is returned, Affected is the scanned rows on query when Logger is setted,
+// the Args is passed to driver directly, the Redacted is the index of Args on redacted column, RedactedArgs should be used when logging it
+type Event struct {
+	Op       string
+	Model    reflect.Type
+	Table    string
+	SQL      string
+	Args     []interface{}
+	Redacted []int
+	Duration time.Duration
+	InsertId int64
+	Affected int64
+	Rows     Rows
+	Err      error
+}
+
+type EventHandler func(ctx context.Context, event *Event) error
+
+// Interceptor is the middleware of sql executing, it can skip next to short-circuit,
+// the Rows should be setted to event when short-circuit on query and query row, the first row of Rows is scanned on query row,
+// the interceptor of query row is called on Row.Scan, not on QueryRow
+type Interceptor func(ctx context.Context, event *Event, next EventHandler) error
+
+func (c *CRUD) intercept(ctx context.Context, event *Event, call EventHandler) (err error) {
+	handler := func(ctx context.Context, event *Event) (err error) {
+		begin := time.Now()
+		err = call(ctx, event)
+		event.Duration = time.Since(begin)
+		event.Err = err
+		return
+	}
+	for i := len(c.Interceptors) - 1; i >= 0; i-- {
+		interceptor, next := c.Interceptors[i], handler
+		handler = func(ctx context.Context, event *Event) error {
+			return interceptor(ctx, event, next)
+		}
+	}
+	err = handler(ctx, event)
+	return
+}
+
+type eventRow struct {
+	c     *CRUD
+	ctx   context.Context
+	event *Event
+	query func(ctx context.Context, sql string, args ...interface{}) Row
+}
+
+func (e *eventRow) Scan(dest ...interface{}) (err error) {
+	called := false
+	err = e.c.intercept(e.ctx, e.event, func(ctx context.Context, event *Event) (err error) {
+		called = true
+		err = e.query(ctx, event.SQL, event.Args...).Scan(dest...)
+		if err == nil {
+			event.Affected = 1
+		}
+		return
+	})
+	if err == nil && !called {
+		err = e.scanRows(dest...)
+	}
+	e.c.logEvent(e.ctx, e.event, err)
+	return
+}
+
+// scanRows will scan the first row of Rows which is setted by short-circuit interceptor
+func (e *eventRow) scanRows(dest ...interface{}) (err error) {
+	rows := e.event.Rows
+	if rows == nil {
+		err = e.c.getErrNoRows()
+		return
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		err = e.c.getErrNoRows()
+		return
+	}
+	err = rows.Scan(dest...)
+	if err == nil {
+		e.event.Affected = 1
+	}
+	return
+}
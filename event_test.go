@@ -0,0 +1,182 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type eventRows struct {
+	Values []int64
+	Closed bool
+}
+
+func (e *eventRows) Scan(dest ...interface{}) (err error) {
+	if v, ok := dest[0].(*int64); ok {
+		*v = e.Values[0]
+	}
+	e.Values = e.Values[1:]
+	return
+}
+func (e *eventRows) Next() bool   { return len(e.Values) > 0 }
+func (e *eventRows) Close() error { e.Closed = true; return nil }
+
+type eventTestRow struct{}
+
+func (e eventTestRow) Scan(dest ...interface{}) (err error) {
+	if v, ok := dest[0].(*int64); ok {
+		*v = 10
+	}
+	return
+}
+
+type eventQueryer struct {
+	SQL  []string
+	Args [][]interface{}
+}
+
+func (e *eventQueryer) Exec(ctx context.Context, query string, args ...interface{}) (insertId, affected int64, err error) {
+	e.SQL = append(e.SQL, query)
+	e.Args = append(e.Args, args)
+	insertId, affected = 1, 2
+	return
+}
+
+func (e *eventQueryer) ExecRow(ctx context.Context, query string, args ...interface{}) (insertId int64, err error) {
+	insertId, _, err = e.Exec(ctx, query, args...)
+	return
+}
+
+func (e *eventQueryer) Query(ctx context.Context, query string, args ...interface{}) (rows Rows, err error) {
+	e.SQL = append(e.SQL, query)
+	e.Args = append(e.Args, args)
+	rows = &eventRows{}
+	return
+}
+
+func (e *eventQueryer) QueryRow(ctx context.Context, query string, args ...interface{}) (row Row) {
+	e.SQL = append(e.SQL, query)
+	e.Args = append(e.Args, args)
+	row = eventTestRow{}
+	return
+}
+
+func TestInterceptor(t *testing.T) {
+	ctx := context.Background()
+	queryer := &eventQueryer{}
+	events := []*Event{}
+	c := NewCRUD(DialectPostgres)
+	c.Interceptors = []Interceptor{
+		func(ctx context.Context, event *Event, next EventHandler) error {
+			events = append(events, event)
+			return next(ctx, event)
+		},
+		func(ctx context.Context, event *Event, next EventHandler) error {
+			if strings.Contains(event.SQL, "deny") {
+				return fmt.Errorf("deny")
+			}
+			if event.Op == OpUpdate {
+				event.SQL += " and 1=1"
+			}
+			return next(ctx, event)
+		},
+	}
+	{ //exec
+		affected, err := c.DeleteWheref(queryer, ctx, &CrudObject{}, "tid=$%v", 1)
+		event := events[len(events)-1]
+		if err != nil || affected != 2 || event.Op != OpDelete || event.Model != reflect.TypeOf(&CrudObject{}) || event.Affected != 2 || event.InsertId != 1 || len(event.Args) != 1 {
+			t.Error(err, event)
+			return
+		}
+	}
+	{ //rewrite
+		affected, err := c.UpdateWheref(queryer, ctx, &CrudObject{Title: "a"}, "title", "tid=$%v", 1)
+		event := events[len(events)-1]
+		if err != nil || affected != 2 || event.Op != OpUpdate || queryer.SQL[len(queryer.SQL)-1] != "update crud_object set title=$1 where tid=$2 and 1=1" {
+			t.Error(err, queryer.SQL)
+			return
+		}
+	}
+	{ //short circuit
+		having := len(queryer.SQL)
+		_, err := c.DeleteWheref(queryer, ctx, &CrudObject{}, "deny=$%v", 1)
+		if err == nil || len(queryer.SQL) != having || events[len(events)-1].Err != nil {
+			t.Error(err)
+			return
+		}
+		var objects []*CrudObject
+		err = c.QueryWheref(queryer, ctx, &CrudObject{}, "#all", "deny=$%v", []interface{}{1}, "", 0, 0, &objects)
+		if err == nil || len(queryer.SQL) != having {
+			t.Error(err)
+			return
+		}
+		_, err = NewRepo[CrudObject](c).Find(ctx, queryer, "deny=$%v", 1)
+		if err == nil || len(queryer.SQL) != having {
+			t.Error(err)
+			return
+		}
+	}
+	{ //query
+		var objects []*CrudObject
+		err := c.QueryWheref(queryer, ctx, &CrudObject{}, "#all", "tid=$%v", []interface{}{1}, "", 0, 0, &objects)
+		event := events[len(events)-1]
+		if err != nil || event.Op != OpQuery || event.Rows == nil || event.Duration <= 0 {
+			t.Error(err, event)
+			return
+		}
+		count, err := NewRepo[CrudObject](c).Count(ctx, queryer, "tid=$%v", 1)
+		event = events[len(events)-1]
+		if err != nil || count != 10 || event.Op != OpCount || !strings.HasPrefix(event.SQL, "select count(*)") {
+			t.Error(err, event)
+			return
+		}
+	}
+	{ //close rows on error
+		c := NewCRUD(DialectPostgres)
+		rows := &eventRows{}
+		c.Interceptors = []Interceptor{
+			func(ctx context.Context, event *Event, next EventHandler) error {
+				event.Rows = rows
+				return fmt.Errorf("error")
+			},
+		}
+		_, err := c.queryerQuery(queryer, ctx, OpQuery, nil, "select 1", nil)
+		if err == nil || !rows.Closed {
+			t.Error(err)
+			return
+		}
+	}
+	{ //short circuit query row
+		c := NewCRUD(DialectPostgres)
+		var rows *eventRows
+		c.Interceptors = []Interceptor{
+			func(ctx context.Context, event *Event, next EventHandler) error {
+				if rows != nil {
+					event.Rows = rows
+				}
+				return nil
+			},
+		}
+		having := len(queryer.SQL)
+		rows = &eventRows{Values: []int64{100}}
+		count, err := NewRepo[CrudObject](c).Count(ctx, queryer, "tid=$%v", 1)
+		if err != nil || count != 100 || !rows.Closed || len(queryer.SQL) != having {
+			t.Error(err, count)
+			return
+		}
+		rows = &eventRows{}
+		_, err = NewRepo[CrudObject](c).Count(ctx, queryer, "tid=$%v", 1)
+		if err != ErrNoRows || !rows.Closed || len(queryer.SQL) != having {
+			t.Error(err)
+			return
+		}
+		rows = nil
+		_, err = NewRepo[CrudObject](c).Count(ctx, queryer, "tid=$%v", 1)
+		if err != ErrNoRows || len(queryer.SQL) != having {
+			t.Error(err)
+			return
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package crud
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Filter is the compiled and validated filter, it can be used on all api which accept filter
+type Filter struct {
+	filter string
+	fields []string
+}
+
+// String will return the normalized filter string
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.filter
+}
+
+// Fields will return all field name used by filter
+func (f *Filter) Fields() []string {
+	return f.fields
+}
+
+func CompileFilter(v interface{}, filter string) (f *Filter, err error) {
+	f, err = Default.CompileFilter(v, filter)
+	return
+}
+
+func MustCompileFilter(v interface{}, filter string) (f *Filter) {
+	f = Default.MustCompileFilter(v, filter)
+	return
+}
+
+func (c *CRUD) MustCompileFilter(v interface{}, filter string) (f *Filter) {
+	f, err := c.CompileFilter(v, filter)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// CompileFilter will parse and validate filter by v, unknown field and bad option will return error
+func (c *CRUD) CompileFilter(v interface{}, filter string) (f *Filter, err error) {
+	var names map[string]bool
+	if _, ok := v.([]interface{}); !ok {
+		reflectType := reflect.Indirect(reflect.ValueOf(v)).Type()
+		if reflectType.Kind() == reflect.Struct {
+			names = map[string]bool{}
+			c.filterNames(reflectType, names)
+		}
+	}
+	f = &Filter{}
+	parts := []string{}
+	for _, part := range strings.Split(filter, "|") {
+		part = strings.TrimSpace(part)
+		parts = append(parts, part)
+		part = strings.TrimPrefix(part, "*")
+		if dot := strings.SplitN(part, ".", 2); len(dot) > 1 {
+			part = dot[1]
+		}
+		options := strings.SplitN(part, "#", 2)
+		if len(options) > 1 && len(options[1]) > 0 {
+			for _, option := range strings.Split(options[1], ",") {
+				switch strings.TrimSpace(option) {
+				case "all", "nil", "zero":
+				default:
+					err = fmt.Errorf("filter %v option %v is not supported", filter, option)
+					return
+				}
+			}
+		}
+		fieldList := strings.TrimSpace(strings.TrimPrefix(options[0], "^"))
+		if len(fieldList) < 1 {
+			continue
+		}
+		for _, fieldItem := range strings.Split(fieldList, ",") {
+			fieldParts := strings.SplitN(strings.Trim(strings.TrimSpace(fieldItem), ")"), "(", 2)
+			fieldName := fieldParts[len(fieldParts)-1]
+			if len(fieldName) < 1 {
+				err = fmt.Errorf("filter %v having empty field", filter)
+				return
+			}
+			if names != nil && !names[fieldName] {
+				err = fmt.Errorf("filter %v field %v is not found on %v", filter, fieldName, reflect.TypeOf(v))
+				return
+			}
+			f.fields = append(f.fields, fieldName)
+		}
+	}
+	f.filter = strings.Join(parts, "|")
+	return
+}
+
+func (c *CRUD) filterNames(reflectType reflect.Type, names map[string]bool) {
+	numField := reflectType.NumField()
+	for i := 0; i < numField; i++ {
+		fieldType := reflectType.Field(i)
+		fieldFilter := strings.TrimSpace(strings.TrimPrefix(fieldType.Tag.Get("filter"), "#"))
+		if strings.Contains(","+fieldFilter+",", ",inline,") {
+			c.filterNames(fieldType.Type, names)
+			continue
+		}
+		fieldName := strings.SplitN(fieldType.Tag.Get(c.Tag), ",", 2)[0]
+		if len(fieldName) < 1 || fieldName == "-" {
+			continue
+		}
+		names[fieldName] = true
+	}
+}
+
+// filterString will convert string/*Filter/Filter to filter string, ErrFilterMismatch is panic when filter type is not supported
+func filterString(filter interface{}) string {
+	switch filter := filter.(type) {
+	case nil:
+		return ""
+	case string:
+		return filter
+	case FilterValue:
+		return string(filter)
+	case *Filter:
+		return filter.String()
+	case Filter:
+		return filter.filter
+	default:
+		panic(&ErrFilterMismatch{Filter: fmt.Sprintf("%v", filter), Type: reflect.TypeOf(filter)})
+	}
+}
@@ -0,0 +1,115 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCompileFilter(t *testing.T) {
+	object := &CrudObject{TID: 100, Title: "title", Level: 1}
+	for _, filter := range []string{
+		"", "*", "#all", "tid,title", "^tid,title#all", "o.tid,title#nil,zero",
+		"o.count(tid),max(user_id)#all", "tid#all|data#all", "*|tid#all", " tid , title ",
+	} {
+		f, err := CompileFilter(object, filter)
+		if err != nil {
+			t.Error(filter, err)
+			return
+		}
+		fmt.Printf("compile %v to %v with fields %v\n", filter, f, f.Fields())
+	}
+	for _, filter := range []string{
+		"tidx", "tid,xxx#all", "^xxx", "tid#xall", "tid#all,none", "tid,#all", "tid|xxx", "count(xxx)",
+	} {
+		_, err := Default.CompileFilter(object, filter)
+		if err == nil {
+			t.Error(filter)
+			return
+		}
+		fmt.Printf("compile %v error %v\n", filter, err)
+	}
+	{ //inline
+		f, err := CompileFilter(&CacheObject{}, "tid,level,title#all")
+		if err != nil || len(f.Fields()) != 3 {
+			t.Error(err)
+			return
+		}
+	}
+	{ //meta
+		_, err := CompileFilter(MetaWith("crud_object", int64(0)), "count(tid)#all")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	{ //must
+		f := MustCompileFilter(object, "tid,title#all")
+		if f.String() != "tid,title#all" {
+			t.Error(f)
+			return
+		}
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("not panic")
+				}
+			}()
+			MustCompileFilter(object, "xxx")
+		}()
+		var nilFilter *Filter
+		if nilFilter.String() != "" || filterString(nilFilter) != "" || filterString(nil) != "" || filterString(FilterValue("tid")) != "tid" || filterString(*f) != "tid,title#all" {
+			t.Error("error")
+			return
+		}
+	}
+	{ //unsupported
+		var filterErr *ErrFilterMismatch
+		err := QueryFilter(&eventQueryer{}, context.Background(), object, 100, nil, "", nil, "", 0, 0)
+		if !errors.As(err, &filterErr) || filterErr.Type != reflect.TypeOf(100) {
+			t.Error(err)
+			return
+		}
+		c := NewCRUD(DialectPostgres)
+		c.Strict = true
+		func() {
+			defer func() {
+				if _, ok := recover().(*ErrFilterMismatch); !ok {
+					t.Error("not panic")
+				}
+			}()
+			c.QueryFilter(&eventQueryer{}, context.Background(), object, []string{"tid"}, nil, "", nil, "", 0, 0)
+		}()
+	}
+}
+
+func TestCompileFilterSQL(t *testing.T) {
+	object := &CrudObject{TID: 100, Title: "title", Level: 1}
+	filter := MustCompileFilter(object, "tid,title,level")
+	sql, args := InsertSQL(object, filter)
+	if sql != "insert into crud_object(tid,level,title) values($1,$2,$3) " || len(args) != 3 {
+		t.Error(sql, args)
+		return
+	}
+	sql, args = UpsertSQL(object, filter, "tid", MustCompileFilter(object, "title"))
+	if sql != "insert into crud_object(tid,level,title) values($1,$2,$3) on conflict (tid) do update set title=excluded.title" || len(args) != 3 {
+		t.Error(sql, args)
+		return
+	}
+	sql = QuerySQL(object, MustCompileFilter(object, "count(tid)#all"))
+	if sql != "select count(tid) from crud_object" {
+		t.Error(sql)
+		return
+	}
+	where, args := Default.FilterWhere(nil, object, filter)
+	if len(where) != 3 || len(args) != 3 {
+		t.Error(where, args)
+		return
+	}
+	if args := ScanArgs(object, filter); len(args) != 3 {
+		t.Error(args)
+		return
+	}
+}
@@ -273,6 +273,7 @@ type AutoGen struct {
 	CodeAddInit   map[string]string
 	CodeTestInit  map[string]string
 	CodeSlice     map[string]string
+	Dialect       crud.Dialect
 	Comments      map[string]map[string]string
 	TableGenAdd   xsql.StringArray
 	TableRetAdd   map[string]string
@@ -506,6 +507,9 @@ func (g *AutoGen) OnPre(gen *Gen, table *Table) (data interface{}) {
 		g.CodeSlice = map[string]string{
 			"RowLock": "",
 		}
+		if g.Dialect != nil {
+			g.CodeSlice["RowLock"] = g.Dialect.RowLock()
+		}
 	}
 	if len(g.TableNameType) < 1 {
 		g.TableNameType = "string"
@@ -678,7 +682,34 @@ func (g *AutoGen) OnPre(gen *Gen, table *Table) (data interface{}) {
 	return
 }
 
+// dialectDefault will fill the empty TableSQL/ColumnSQL/TypeMap/CodeSlice by Dialect
+func (g *AutoGen) dialectDefault() {
+	if g.Dialect == nil {
+		return
+	}
+	tableSQL, columnSQL, typeMap, codeSlice := TableSQLPG, ColumnSQLPG, TypeMapPG, CodeSlicePG
+	switch g.Dialect.Name() {
+	case "mysql":
+		tableSQL, columnSQL, typeMap, codeSlice = TableSQLMYSQL, ColumnSQLMYSQL, TypeMapMYSQL, CodeSliceMYSQL
+	case "sqlite":
+		tableSQL, columnSQL, typeMap, codeSlice = TableSQLSQLITE, ColumnSQLSQLITE, TypeMapSQLITE, CodeSliceSQLITE
+	}
+	if len(g.TableSQL) < 1 {
+		g.TableSQL = tableSQL
+	}
+	if len(g.ColumnSQL) < 1 {
+		g.ColumnSQL = columnSQL
+	}
+	if g.TypeMap == nil {
+		g.TypeMap = typeMap
+	}
+	if g.CodeSlice == nil {
+		g.CodeSlice = codeSlice
+	}
+}
+
 func (g *AutoGen) Generate() (err error) {
+	g.dialectDefault()
 	if g.TypeMap == nil {
 		g.TypeMap = map[string][]string{}
 	}
@@ -3,14 +3,17 @@ package gen
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/codingeasygo/crud"
 	"github.com/codingeasygo/crud/sqlx"
 	"github.com/codingeasygo/crud/testsql"
 	"github.com/codingeasygo/util/xsql"
@@ -311,3 +314,141 @@ func TestSqliteGen(t *testing.T) {
 		return
 	}
 }
+
+func TestMysqlGen(t *testing.T) {
+	g := &AutoGen{Dialect: crud.DialectMySQL}
+	g.dialectDefault()
+	if g.TableSQL != TableSQLMYSQL || g.ColumnSQL != ColumnSQLMYSQL || g.CodeSlice["RowLock"] != "for update" || g.TypeMap["bigint"][0] != "int64" {
+		t.Error("error")
+		return
+	}
+	if !strings.Contains(TableSQLMYSQL, "information_schema.tables") || strings.Count(ColumnSQLMYSQL, "?") != 2 {
+		t.Error("error")
+		return
+	}
+	s := &Struct{}
+	for typ, types := range map[string][]string{"varchar(255)": {"string", "*string"}, "bigint(20)": {"int64", "*int64"}, "datetime": {"xsql.Time", "xsql.Time"}, "json": {"xsql.M", "xsql.M"}} {
+		if having := ConvSizeTrim(TypeMapMYSQL, s, &Column{Type: typ, NotNull: true}); having != types[0] {
+			t.Error(typ, having)
+			return
+		}
+		if having := ConvSizeTrim(TypeMapMYSQL, s, &Column{Type: typ}); having != types[1] {
+			t.Error(typ, having)
+			return
+		}
+	}
+	if NameConvMYSQL("query", "tid", reflect.StructField{}) != "tid" {
+		t.Error("error")
+		return
+	}
+	if value := ParmConvMYSQL("where", "tid", "", reflect.StructField{}, xsql.Int64Array{1, 2}); value != "1,2" {
+		t.Error(value)
+		return
+	}
+	if value := ParmConvMYSQL("insert", "tid", "", reflect.StructField{}, 1); value != 1 {
+		t.Error(value)
+		return
+	}
+	g = &AutoGen{Dialect: crud.DialectSQLite, TableSQL: "xxx"}
+	g.dialectDefault()
+	if g.TableSQL != "xxx" || g.ColumnSQL != ColumnSQLSQLITE {
+		t.Error("error")
+		return
+	}
+}
+
+func mysqlColumn(name, typ, ddlType string, isPK, notNull bool, defaultValue string) *Column {
+	column := &Column{Name: name, Type: typ, IsPK: isPK, NotNull: notNull, DDLType: ddlType}
+	if len(defaultValue) > 0 {
+		column.DefaultValue = &defaultValue
+	}
+	return column
+}
+
+// MysqlTables is the crud_object table which is returned by TableSQLMYSQL/ColumnSQLMYSQL on mysql
+var MysqlTables = []*Table{
+	{
+		Name:    "crud_object",
+		Type:    "BASE TABLE",
+		Comment: "crud object",
+		Columns: []*Column{
+			mysqlColumn("tid", "bigint", "bigint auto_increment", true, true, ""),
+			mysqlColumn("user_id", "bigint", "bigint", false, true, "0"),
+			mysqlColumn("type", "varchar", "varchar(32)", false, true, ""),
+			mysqlColumn("level", "int", "int", false, true, "0"),
+			mysqlColumn("title", "varchar", "varchar(255)", false, true, ""),
+			mysqlColumn("image", "text", "text", false, false, ""),
+			mysqlColumn("data", "json", "json", false, true, ""),
+			mysqlColumn("int_ptr", "int", "int", false, false, ""),
+			mysqlColumn("float64_value", "double", "double", false, true, "0"),
+			mysqlColumn("enabled", "bit", "bit(1)", false, true, "0"),
+			mysqlColumn("update_time", "datetime", "datetime", false, true, ""),
+			mysqlColumn("create_time", "datetime", "datetime", false, true, ""),
+			mysqlColumn("status", "int", "int", false, true, ""),
+		},
+	},
+}
+
+var MysqlGen = AutoGen{
+	Dialect: crud.DialectMySQL,
+	Comments: map[string]map[string]string{
+		"crud_object": {
+			"type":   `simple type in, A=a:test a, B=b:test b`,
+			"status": `simple status in, Normal=100, Disabled=200, Removed=-1`,
+		},
+	},
+	TableQueryer: func(queryer interface{}, tableSQL, columnSQL, schema string) (tables []*Table, err error) {
+		if tableSQL != TableSQLMYSQL || columnSQL != ColumnSQLMYSQL {
+			err = fmt.Errorf("not mysql")
+			return
+		}
+		tables = MysqlTables
+		return
+	},
+	Schema:     "crud",
+	NameConv:   nameConv,
+	GetQueryer: "GetQueryer",
+	Out:        "./autogen_mysql/",
+	OutPackage: "autogen",
+}
+
+func TestMysqlGenerate(t *testing.T) {
+	var err error
+	defer func() {
+		if err == nil {
+			os.RemoveAll(MysqlGen.Out)
+		}
+	}()
+	os.MkdirAll(MysqlGen.Out, os.ModePerm)
+	err = MysqlGen.Generate()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	models, err := ioutil.ReadFile(filepath.Join(MysqlGen.Out, "auto_models.go"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, field := range []string{"TID          int64 ", "Title        string ", "Image        *string ", "Data         xsql.M ", "IntPtr       *int ", "Float64Value decimal.Decimal ", "Enabled      bool ", "UpdateTime   xsql.Time ", "Status       CrudObjectStatus "} {
+		if !strings.Contains(string(models), field) {
+			t.Errorf("%v not found on\n%v", field, string(models))
+			return
+		}
+	}
+	funcs, err := ioutil.ReadFile(filepath.Join(MysqlGen.Out, "auto_func.go"))
+	if err != nil || !strings.Contains(string(funcs), `querySQL += " for update "`) {
+		t.Error(err)
+		return
+	}
+	pwd, _ := os.Getwd()
+	builder := exec.Command("go", "vet", ".")
+	builder.Dir = filepath.Join(pwd, "autogen_mysql")
+	builder.Stderr = os.Stderr
+	builder.Stdout = os.Stdout
+	err = builder.Run()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+}
@@ -0,0 +1,81 @@
+package gen
+
+import (
+	"reflect"
+
+	"github.com/codingeasygo/crud"
+)
+
+const TableSQLMYSQL = `
+SELECT
+    table_name AS name,
+    table_type AS type,
+    coalesce(table_comment,'') AS comment
+FROM information_schema.tables
+WHERE table_schema = ?
+AND table_type = 'BASE TABLE'
+ORDER BY table_name
+`
+
+const ColumnSQLMYSQL = `
+SELECT
+    column_name AS name,
+    data_type AS type,
+    column_key = 'PRI' AS is_pk,
+    is_nullable = 'NO' AS not_null,
+    column_default AS default_value,
+    ordinal_position AS ordinal,
+    CASE
+        WHEN extra LIKE '%auto_increment%' THEN concat(column_type, ' auto_increment')
+        ELSE column_type
+    END AS ddl_type,
+    coalesce(column_comment,'') AS comment
+FROM information_schema.columns
+WHERE table_schema = ?
+    AND table_name = ?
+ORDER BY ordinal_position
+`
+
+var TypeMapMYSQL = map[string][]string{
+	//int
+	"tinyint":   {"int", "*int"},
+	"smallint":  {"int", "*int"},
+	"mediumint": {"int", "*int"},
+	"int":       {"int", "*int"},
+	"integer":   {"int", "*int"},
+	"bigint":    {"int64", "*int64"},
+	//float
+	"float":   {"decimal.Decimal", "decimal.Decimal"},
+	"double":  {"decimal.Decimal", "decimal.Decimal"},
+	"decimal": {"decimal.Decimal", "decimal.Decimal"},
+	"numeric": {"decimal.Decimal", "decimal.Decimal"},
+	//string
+	"char":       {"string", "*string"},
+	"varchar":    {"string", "*string"},
+	"tinytext":   {"string", "*string"},
+	"text":       {"string", "*string"},
+	"mediumtext": {"string", "*string"},
+	"longtext":   {"string", "*string"},
+	"enum":       {"string", "*string"},
+	//time
+	"date":      {"xsql.Time", "xsql.Time"},
+	"datetime":  {"xsql.Time", "xsql.Time"},
+	"timestamp": {"xsql.Time", "xsql.Time"},
+	//bool
+	"bit":     {"bool", "*bool"},
+	"boolean": {"bool", "*bool"},
+	//json
+	"json": {"xsql.M", "xsql.M"},
+}
+
+var CodeSliceMYSQL = map[string]string{
+	"RowLock": crud.DialectMySQL.RowLock(),
+}
+
+func NameConvMYSQL(on, name string, field reflect.StructField) string {
+	return name
+}
+
+func ParmConvMYSQL(on, fieldName, fieldFunc string, field reflect.StructField, value interface{}) interface{} {
+	return crud.DialectMySQL.ParmConv(on, fieldName, fieldFunc, field, value)
+}
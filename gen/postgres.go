@@ -4,7 +4,7 @@ import (
 	"reflect"
 	"strings"
 
-	"github.com/codingeasygo/util/xsql"
+	"github.com/codingeasygo/crud"
 )
 
 const TableSQLPG = `
@@ -90,7 +90,7 @@ var TypeMapPG = map[string][]string{
 }
 
 var CodeSlicePG = map[string]string{
-	"RowLock": "for update",
+	"RowLock": crud.DialectPostgres.RowLock(),
 }
 
 func NameConvPG(on, name string, field reflect.StructField) string {
@@ -102,8 +102,5 @@ func NameConvPG(on, name string, field reflect.StructField) string {
 }
 
 func ParmConvPG(on, fieldName, fieldFunc string, field reflect.StructField, value interface{}) interface{} {
-	if c, ok := value.(xsql.ArrayConverter); on == "where" && ok {
-		return c.DbArray()
-	}
-	return value
+	return crud.DialectPostgres.ParmConv(on, fieldName, fieldFunc, field, value)
 }
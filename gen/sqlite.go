@@ -3,7 +3,7 @@ package gen
 import (
 	"reflect"
 
-	"github.com/codingeasygo/util/xsql"
+	"github.com/codingeasygo/crud"
 )
 
 const TableSQLSQLITE = `
@@ -43,7 +43,7 @@ var TypeMapSQLITE = map[string][]string{
 }
 
 var CodeSliceSQLITE = map[string]string{
-	"RowLock": "",
+	"RowLock": crud.DialectSQLite.RowLock(),
 }
 
 func NameConvSQLITE(on, name string, field reflect.StructField) string {
@@ -51,8 +51,5 @@ func NameConvSQLITE(on, name string, field reflect.StructField) string {
 }
 
 func ParmConvSQLITE(on, fieldName, fieldFunc string, field reflect.StructField, value interface{}) interface{} {
-	if c, ok := value.(xsql.ArrayConverter); on == "where" && ok {
-		return c.InArray()
-	}
-	return value
+	return crud.DialectSQLite.ParmConv(on, fieldName, fieldFunc, field, value)
 }
@@ -204,7 +204,7 @@ func ({{.Arg.Name}} *{{.Struct.Name}}) UpdateFilterWheref(caller interface{}, ct
 {{if .Add.Normal}}
 //Add{{.Struct.Name}} will add {{.Struct.Table.Name}} to database
 func Add{{.Struct.Name}}(ctx context.Context, {{.Arg.Name}} *{{.Struct.Name}}) (err error) {
-	err = Add{{.Struct.Name}}Call(GetQueryer, ctx, {{.Arg.Name}})
+	err = Add{{.Struct.Name}}Call(crud.QueryerFrom(ctx, GetQueryer), ctx, {{.Arg.Name}})
 	return
 }
 
@@ -217,7 +217,7 @@ func Add{{.Struct.Name}}Call(caller interface{}, ctx context.Context, {{.Arg.Nam
 
 //Update{{.Struct.Name}}Filter will update {{.Struct.Table.Name}} to database
 func Update{{.Struct.Name}}Filter(ctx context.Context, {{.Arg.Name}} *{{.Struct.Name}}, filter string) (err error) {
-	err = Update{{.Struct.Name}}FilterCall(GetQueryer, ctx, {{.Arg.Name}}, filter)
+	err = Update{{.Struct.Name}}FilterCall(crud.QueryerFrom(ctx, GetQueryer), ctx, {{.Arg.Name}}, filter)
 	return
 }
 
@@ -229,7 +229,7 @@ func Update{{.Struct.Name}}FilterCall(caller interface{}, ctx context.Context, {
 
 //Update{{.Struct.Name}}Wheref will update {{.Struct.Table.Name}} to database
 func Update{{.Struct.Name}}Wheref(ctx context.Context, {{.Arg.Name}} *{{.Struct.Name}}, formats string, formatArgs ...interface{}) (err error) {
-	err = Update{{.Struct.Name}}WherefCall(GetQueryer, ctx, {{.Arg.Name}}, formats, formatArgs...)
+	err = Update{{.Struct.Name}}WherefCall(crud.QueryerFrom(ctx, GetQueryer), ctx, {{.Arg.Name}}, formats, formatArgs...)
 	return
 }
 
@@ -241,7 +241,7 @@ func Update{{.Struct.Name}}WherefCall(caller interface{}, ctx context.Context, {
 
 //Update{{.Struct.Name}}FilterWheref will update {{.Struct.Table.Name}} to database
 func Update{{.Struct.Name}}FilterWheref(ctx context.Context, {{.Arg.Name}} *{{.Struct.Name}}, filter string, formats string, formatArgs ...interface{}) (err error) {
-	err = Update{{.Struct.Name}}FilterWherefCall(GetQueryer, ctx, {{.Arg.Name}}, filter, formats, formatArgs...)
+	err = Update{{.Struct.Name}}FilterWherefCall(crud.QueryerFrom(ctx, GetQueryer), ctx, {{.Arg.Name}}, filter, formats, formatArgs...)
 	return
 }
 
@@ -253,7 +253,7 @@ func Update{{.Struct.Name}}FilterWherefCall(caller interface{}, ctx context.Cont
 
 //Find{{.Struct.Name}}Call will find {{.Struct.Table.Name}} by id from database
 func Find{{.Struct.Name}}(ctx context.Context, {{.Arg.Name}}ID {{PrimaryField .Struct "Type"}}) ({{.Arg.Name}} *{{.Struct.Name}}, err error) {
-	{{.Arg.Name}}, err = Find{{.Struct.Name}}Call(GetQueryer, ctx, {{.Arg.Name}}ID, false)
+	{{.Arg.Name}}, err = Find{{.Struct.Name}}Call(crud.QueryerFrom(ctx, GetQueryer), ctx, {{.Arg.Name}}ID, false)
 	return
 }
 
@@ -277,19 +277,19 @@ func Find{{.Struct.Name}}WhereCall(caller interface{}, ctx context.Context, lock
 
 //Find{{.Struct.Name}}Wheref will find {{.Struct.Table.Name}} by where from database
 func Find{{.Struct.Name}}Wheref(ctx context.Context, format string, args ...interface{}) ({{.Arg.Name}} *{{.Struct.Name}}, err error) {
-	{{.Arg.Name}}, err = Find{{.Struct.Name}}WherefCall(GetQueryer, ctx, false, format, args...)
+	{{.Arg.Name}}, err = Find{{.Struct.Name}}WherefCall(crud.QueryerFrom(ctx, GetQueryer), ctx, false, format, args...)
 	return
 }
 
 //Find{{.Struct.Name}}WherefCall will find {{.Struct.Table.Name}} by where from database
 func Find{{.Struct.Name}}WherefCall(caller interface{}, ctx context.Context, lock bool, format string, args ...interface{}) ({{.Arg.Name}} *{{.Struct.Name}}, err error) {
-	{{.Arg.Name}}, err = Find{{.Struct.Name}}FilterWherefCall(GetQueryer, ctx, lock, "{{.Filter.Find}}", format, args...)
+	{{.Arg.Name}}, err = Find{{.Struct.Name}}FilterWherefCall(caller, ctx, lock, "{{.Filter.Find}}", format, args...)
 	return
 }
 
 //Find{{.Struct.Name}}FilterWheref will find {{.Struct.Table.Name}} by where from database
 func Find{{.Struct.Name}}FilterWheref(ctx context.Context, filter string, format string, args ...interface{}) ({{.Arg.Name}} *{{.Struct.Name}}, err error) {
-	{{.Arg.Name}}, err = Find{{.Struct.Name}}FilterWherefCall(GetQueryer, ctx, false, filter, format, args...)
+	{{.Arg.Name}}, err = Find{{.Struct.Name}}FilterWherefCall(crud.QueryerFrom(ctx, GetQueryer), ctx, false, filter, format, args...)
 	return
 }
 
@@ -307,7 +307,7 @@ func Find{{.Struct.Name}}FilterWherefCall(caller interface{}, ctx context.Contex
 
 //List{{.Struct.Name}}ByID will list {{.Struct.Table.Name}} by id from database
 func List{{.Struct.Name}}ByID(ctx context.Context, {{.Arg.Name}}IDs ...{{PrimaryField .Struct "Type"}}) ({{.Arg.Name}}List []*{{.Struct.Name}}, {{.Arg.Name}}Map map[{{PrimaryField .Struct "Type"}}]*{{.Struct.Name}}, err error) {
-	{{.Arg.Name}}List, {{.Arg.Name}}Map, err = List{{.Struct.Name}}ByIDCall(GetQueryer, ctx, {{.Arg.Name}}IDs...)
+	{{.Arg.Name}}List, {{.Arg.Name}}Map, err = List{{.Struct.Name}}ByIDCall(crud.QueryerFrom(ctx, GetQueryer), ctx, {{.Arg.Name}}IDs...)
 	return
 }
 
@@ -323,7 +323,7 @@ func List{{.Struct.Name}}ByIDCall(caller interface{}, ctx context.Context, {{.Ar
 
 //List{{.Struct.Name}}FilterByID will list {{.Struct.Table.Name}} by id from database
 func List{{.Struct.Name}}FilterByID(ctx context.Context, filter string, {{.Arg.Name}}IDs ...{{PrimaryField .Struct "Type"}}) ({{.Arg.Name}}List []*{{.Struct.Name}}, {{.Arg.Name}}Map map[{{PrimaryField .Struct "Type"}}]*{{.Struct.Name}}, err error) {
-	{{.Arg.Name}}List, {{.Arg.Name}}Map, err = List{{.Struct.Name}}FilterByIDCall(GetQueryer, ctx, filter, {{.Arg.Name}}IDs...)
+	{{.Arg.Name}}List, {{.Arg.Name}}Map, err = List{{.Struct.Name}}FilterByIDCall(crud.QueryerFrom(ctx, GetQueryer), ctx, filter, {{.Arg.Name}}IDs...)
 	return
 }
 
@@ -339,7 +339,7 @@ func List{{.Struct.Name}}FilterByIDCall(caller interface{}, ctx context.Context,
 
 //List{{.Struct.Name}}Wheref will list {{.Struct.Table.Name}} from database
 func List{{.Struct.Name}}Wheref(ctx context.Context, format string, args ...interface{}) ({{.Arg.Name}}List []*{{.Struct.Name}}, {{.Arg.Name}}Map map[{{PrimaryField .Struct "Type"}}]*{{.Struct.Name}}, err error) {
-	{{.Arg.Name}}List, {{.Arg.Name}}Map, err = List{{.Struct.Name}}WherefCall(GetQueryer, ctx, format, args...)
+	{{.Arg.Name}}List, {{.Arg.Name}}Map, err = List{{.Struct.Name}}WherefCall(crud.QueryerFrom(ctx, GetQueryer), ctx, format, args...)
 	return
 }
 
@@ -351,7 +351,7 @@ func List{{.Struct.Name}}WherefCall(caller interface{}, ctx context.Context, for
 
 //Scan{{.Struct.Name}}ByID will list {{.Struct.Table.Name}} by id from database
 func Scan{{.Struct.Name}}ByID(ctx context.Context, {{.Arg.Name}}IDs []{{PrimaryField .Struct "Type"}}, dest ...interface{}) (err error) {
-	err = Scan{{.Struct.Name}}ByIDCall(GetQueryer, ctx, {{.Arg.Name}}IDs, dest...)
+	err = Scan{{.Struct.Name}}ByIDCall(crud.QueryerFrom(ctx, GetQueryer), ctx, {{.Arg.Name}}IDs, dest...)
 	return
 }
 
@@ -363,7 +363,7 @@ func Scan{{.Struct.Name}}ByIDCall(caller interface{}, ctx context.Context, {{.Ar
 
 //Scan{{.Struct.Name}}FilterByID will list {{.Struct.Table.Name}} by id from database
 func Scan{{.Struct.Name}}FilterByID(ctx context.Context, filter string, {{.Arg.Name}}IDs []{{PrimaryField .Struct "Type"}}, dest ...interface{}) (err error) {
-	err = Scan{{.Struct.Name}}FilterByIDCall(GetQueryer, ctx, filter, {{.Arg.Name}}IDs, dest...)
+	err = Scan{{.Struct.Name}}FilterByIDCall(crud.QueryerFrom(ctx, GetQueryer), ctx, filter, {{.Arg.Name}}IDs, dest...)
 	return
 }
 
@@ -378,7 +378,7 @@ func Scan{{.Struct.Name}}FilterByIDCall(caller interface{}, ctx context.Context,
 
 //Scan{{.Struct.Name}}WherefCall will list {{.Struct.Table.Name}} by format from database
 func Scan{{.Struct.Name}}Wheref(ctx context.Context, format string, args []interface{}, suffix string, dest ...interface{}) (err error) {
-	err = Scan{{.Struct.Name}}WherefCall(GetQueryer, ctx, format, args, suffix, dest...)
+	err = Scan{{.Struct.Name}}WherefCall(crud.QueryerFrom(ctx, GetQueryer), ctx, format, args, suffix, dest...)
 	return
 }
 
@@ -390,7 +390,7 @@ func Scan{{.Struct.Name}}WherefCall(caller interface{}, ctx context.Context, for
 
 //Scan{{.Struct.Name}}FilterWheref will list {{.Struct.Table.Name}} by format from database
 func Scan{{.Struct.Name}}FilterWheref(ctx context.Context, filter string, format string, args []interface{}, suffix string, dest ...interface{}) (err error) {
-	err = Scan{{.Struct.Name}}FilterWherefCall(GetQueryer, ctx, filter, format, args, suffix, dest...)
+	err = Scan{{.Struct.Name}}FilterWherefCall(crud.QueryerFrom(ctx, GetQueryer), ctx, filter, format, args, suffix, dest...)
 	return
 }
 
@@ -447,6 +447,12 @@ func TestAuto{{.Struct.Name}}(t *testing.T) {
 		return
 	}
 	{{.Arg.Name}} := &{{.Struct.Name}}{}
+	for _, filter := range []string{ {{.Struct.Name}}FilterInsert, {{.Struct.Name}}FilterUpdate, {{.Struct.Name}}FilterFind, {{.Struct.Name}}FilterScan } {
+		if _, err = crud.CompileFilter({{.Arg.Name}}, filter); err != nil {
+			t.Error(err)
+			return
+		}
+	}
 	{{- if .GenValid}}
 	{{.Arg.Name}}.Valid()
 	{{- end}}
@@ -501,6 +507,14 @@ func TestAuto{{.Struct.Name}}(t *testing.T) {
 		t.Error("find id error")
 		return
 	}
+	err = crud.WithTx(context.Background(), GetQueryer, nil, func(ctx context.Context, tx crud.Queryer) (err error) {
+		find{{.Struct.Name}}, err = Find{{.Struct.Name}}(ctx, {{.Arg.Name}}.{{PrimaryField .Struct "Name"}})
+		return
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
 	find{{.Struct.Name}}, err = Find{{.Struct.Name}}Wheref(context.Background(), "{{PrimaryField .Struct "Column"}}=$%v", {{.Arg.Name}}.{{PrimaryField .Struct "Name"}})
 	if err != nil {
 		t.Error(err)
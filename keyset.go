@@ -0,0 +1,230 @@
+package crud
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrCursorInvalid is returned when the keyset cursor is malformed or tampered
+var ErrCursorInvalid = fmt.Errorf("cursor is invalid")
+
+// ErrCursorKeyMissing is returned when keyset paging is used but CRUD.CursorKey is not setted
+var ErrCursorKeyMissing = fmt.Errorf("CRUD.CursorKey is required by keyset paging")
+
+var keysetTimeType = reflect.TypeOf(time.Time{})
+
+type keysetMeta struct {
+	tag     string
+	columns []string
+	desc    bool
+	model   reflect.Value
+	cursor  reflect.Value
+	next    reflect.Value
+	limit   int
+}
+
+func (k *keysetMeta) orderby() string {
+	direction := " desc"
+	if !k.desc {
+		direction = " asc"
+	}
+	return "order by " + strings.Join(k.columns, direction+",") + direction
+}
+
+// keysetUnify will parse Page.Cursor keyset tag like keyset:"create_time,tid#asc" on unify struct, nil is returned when not keyset page
+func (c *CRUD) keysetUnify(v interface{}) (keyset *keysetMeta) {
+	reflectValue := reflect.Indirect(reflect.ValueOf(v))
+	pageValue := reflectValue.FieldByName("Page")
+	if !pageValue.IsValid() || pageValue.Kind() != reflect.Struct {
+		return
+	}
+	cursorType, ok := pageValue.Type().FieldByName("Cursor")
+	tag := cursorType.Tag.Get("keyset")
+	if !ok || len(tag) < 1 || cursorType.Type.Kind() != reflect.String {
+		return
+	}
+	keyset = &keysetMeta{
+		tag:    tag,
+		desc:   true,
+		model:  reflectValue.FieldByName("Model"),
+		cursor: pageValue.FieldByName("Cursor"),
+		next:   pageValue.FieldByName("NextCursor"),
+	}
+	parts := strings.SplitN(tag, "#", 2)
+	for _, column := range strings.Split(parts[0], ",") {
+		if column = strings.TrimSpace(column); len(column) > 0 {
+			keyset.columns = append(keyset.columns, column)
+		}
+	}
+	if len(parts) > 1 && strings.TrimSpace(parts[1]) == "asc" {
+		keyset.desc = false
+	}
+	pageType, _ := reflectValue.Type().FieldByName("Page")
+	keyset.limit = c.limitUnify(pageType, pageValue)
+	return
+}
+
+func (c *CRUD) keysetField(value reflect.Value, column string) (field reflect.Value) {
+	if parts := strings.SplitN(column, ".", 2); len(parts) > 1 {
+		column = parts[1]
+	}
+	value = reflect.Indirect(value)
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		fieldType := valueType.Field(i)
+		fieldFilter := strings.TrimSpace(strings.TrimPrefix(fieldType.Tag.Get("filter"), "#"))
+		if strings.Contains(","+fieldFilter+",", ",inline,") {
+			if field = c.keysetField(value.Field(i), column); field.IsValid() {
+				return
+			}
+			continue
+		}
+		if strings.SplitN(fieldType.Tag.Get(c.Tag), ",", 2)[0] == column {
+			field = value.Field(i)
+			return
+		}
+	}
+	return
+}
+
+func (c *CRUD) cursorSign(keyset *keysetMeta, payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.CursorKey)
+	mac.Write([]byte(keyset.tag))
+	mac.Write([]byte{0})
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// keysetValues will verify and decode cursor to column values, the value is pointer of model field type
+func (c *CRUD) keysetValues(keyset *keysetMeta, cursor string) (values []interface{}, err error) {
+	if len(c.CursorKey) < 1 {
+		err = ErrCursorKeyMissing
+		return
+	}
+	parts := strings.SplitN(cursor, ".", 2)
+	if len(parts) != 2 {
+		err = ErrCursorInvalid
+		return
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		err = ErrCursorInvalid
+		return
+	}
+	sign, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sign, c.cursorSign(keyset, payload)) {
+		err = ErrCursorInvalid
+		return
+	}
+	raws := []json.RawMessage{}
+	if err = json.Unmarshal(payload, &raws); err != nil || len(raws) != len(keyset.columns) {
+		err = ErrCursorInvalid
+		return
+	}
+	for i, column := range keyset.columns {
+		field := c.keysetField(keyset.model, column)
+		if !field.IsValid() {
+			err = fmt.Errorf("keyset column %v is not found on %v", column, keyset.model.Type())
+			return
+		}
+		value := reflect.New(field.Type())
+		if field.Type().ConvertibleTo(keysetTimeType) {
+			var timeValue time.Time
+			if err = json.Unmarshal(raws[i], &timeValue); err != nil {
+				err = ErrCursorInvalid
+				return
+			}
+			value.Elem().Set(reflect.ValueOf(timeValue).Convert(field.Type()))
+		} else if err = json.Unmarshal(raws[i], value.Interface()); err != nil {
+			err = ErrCursorInvalid
+			return
+		}
+		values = append(values, value.Interface())
+	}
+	return
+}
+
+// keysetCursor will encode and sign cursor by column values of value, the time column is encoded by RFC3339Nano to keep full precision
+func (c *CRUD) keysetCursor(keyset *keysetMeta, value reflect.Value) (cursor string, err error) {
+	if len(c.CursorKey) < 1 {
+		err = ErrCursorKeyMissing
+		return
+	}
+	values := []interface{}{}
+	for _, column := range keyset.columns {
+		field := c.keysetField(value, column)
+		if !field.IsValid() {
+			err = fmt.Errorf("keyset column %v is not found on %v", column, value.Type())
+			return
+		}
+		if field.Type().ConvertibleTo(keysetTimeType) {
+			values = append(values, field.Convert(keysetTimeType).Interface())
+			continue
+		}
+		if field.CanAddr() {
+			field = field.Addr()
+		}
+		values = append(values, field.Interface())
+	}
+	payload, err := json.Marshal(values)
+	if err != nil {
+		return
+	}
+	cursor = base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(c.cursorSign(keyset, payload))
+	return
+}
+
+// keysetNext will set Page.NextCursor by last scanned value, it is empty when there is no more page
+func (c *CRUD) keysetNext(keyset *keysetMeta, last reflect.Value, scanned int) (err error) {
+	if !keyset.next.IsValid() || !keyset.next.CanSet() {
+		return
+	}
+	cursor := ""
+	if last.IsValid() && keyset.limit > 0 && scanned >= keyset.limit {
+		cursor, err = c.keysetCursor(keyset, last)
+		if err != nil {
+			return
+		}
+	}
+	keyset.next.SetString(cursor)
+	return
+}
+
+func (c *CRUD) joinWhereKeyset(caller int, ctx context.Context, sql string, args []interface{}, v interface{}) (sql_ string, args_ []interface{}, err error) {
+	keyset := c.keysetUnify(v)
+	if keyset == nil || len(keyset.cursor.String()) < 1 {
+		sql_, args_, err = c.joinWhereUnify(caller+1, ctx, sql, args, v)
+		return
+	}
+	values, err := c.keysetValues(keyset, keyset.cursor.String())
+	if err != nil {
+		if c.Verbose {
+			c.Log(caller, "CRUD join keyset where by struct:%v,cursor:%v, result is fail:%v", reflect.TypeOf(v), keyset.cursor.String(), err)
+		}
+		return
+	}
+	where, args_ := c.AppendWhereUnify(nil, args, v)
+	where, args_, whereJoin, err := c.scopeWhere(c.unifyScope(ctx, v), c.unifyModel(v), where, args_, c.whereJoinUnify(v))
+	if err != nil {
+		return
+	}
+	placeholders := []string{}
+	for _, value := range values {
+		args_ = append(args_, value)
+		placeholders = append(placeholders, c.placeholder(len(args_)))
+	}
+	cmp := "<"
+	if !keyset.desc {
+		cmp = ">"
+	}
+	where, _ = c.groupWhere(where, whereJoin, fmt.Sprintf("(%v) %v (%v)", strings.Join(keyset.columns, ","), cmp, strings.Join(placeholders, ",")))
+	sql_ = c.joinWhere(caller+1, sql, where, "and")
+	return
+}
@@ -0,0 +1,231 @@
+package crud
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codingeasygo/util/xsql"
+)
+
+type KeysetCrudObjectUnify struct {
+	Model CrudObject `json:"model"`
+	Where struct {
+		UserID int64  `json:"user_id"`
+		Title  string `json:"title" cmp:"title like $%v"`
+	} `json:"where" join:"or"`
+	Page struct {
+		Order      string `json:"order" default:"order by tid asc"`
+		Offset     int    `json:"offset"`
+		Limit      int    `json:"limit"`
+		Cursor     string `json:"cursor" keyset:"create_time,tid"`
+		NextCursor string `json:"next_cursor"`
+	} `json:"page"`
+	Query struct {
+		Objects []*CrudObject `json:"objects"`
+	} `json:"query" filter:"tid,create_time#all"`
+}
+
+type keysetRows struct {
+	Total int
+	Index int
+}
+
+func (k *keysetRows) Next() bool   { k.Index++; return k.Index <= k.Total }
+func (k *keysetRows) Close() error { return nil }
+func (k *keysetRows) Scan(dest ...interface{}) (err error) {
+	for _, d := range dest {
+		switch d := d.(type) {
+		case *int64:
+			*d = int64(100 - k.Index)
+		case *xsql.Time:
+			*d = xsql.Time(time.UnixMilli(int64(1000000 - k.Index)))
+		}
+	}
+	return
+}
+
+type keysetQueryer struct {
+	eventQueryer
+	Args  [][]interface{}
+	Total int
+}
+
+func (k *keysetQueryer) Query(ctx context.Context, query string, args ...interface{}) (rows Rows, err error) {
+	k.SQL = append(k.SQL, query)
+	k.Args = append(k.Args, args)
+	rows = &keysetRows{Total: k.Total}
+	return
+}
+
+func TestKeyset(t *testing.T) {
+	Default.CursorKey = []byte("test")
+	defer func() { Default.CursorKey = nil }()
+	ctx := context.Background()
+	queryer := &keysetQueryer{Total: 2}
+	search := &KeysetCrudObjectUnify{}
+	search.Where.UserID = 100
+	search.Page.Limit = 2
+	sql, args := QueryUnifySQL(search, "Query")
+	if sql != "select tid,create_time from crud_object where user_id = $1  order by create_time desc,tid desc limit 2 offset 0" || len(args) != 1 {
+		t.Error(sql, args)
+		return
+	}
+	err := QueryUnify(queryer, ctx, search)
+	if err != nil || len(search.Query.Objects) != 2 || len(search.Page.NextCursor) < 1 {
+		t.Error(err, search.Page.NextCursor)
+		return
+	}
+	{ //next page
+		search.Page.Cursor = search.Page.NextCursor
+		search.Page.Offset = 10
+		search.Where.Title = "%a%"
+		search.Query.Objects = nil
+		queryer.Total = 1
+		err = QueryUnify(queryer, ctx, search)
+		sql, args = queryer.SQL[1], queryer.Args[1]
+		if err != nil || sql != "select tid,create_time from crud_object where (user_id = $1 or title like $2) and (create_time,tid) < ($3,$4)  order by create_time desc,tid desc limit 2 offset 0" || len(args) != 4 {
+			t.Error(err, sql, args)
+			return
+		}
+		if args[2].(*xsql.Time).Timestamp() != 1000000-2 || *args[3].(*int64) != 98 {
+			t.Error(args)
+			return
+		}
+		if len(search.Page.NextCursor) > 0 || len(search.Query.Objects) != 1 {
+			t.Error(search.Page.NextCursor)
+			return
+		}
+	}
+	{ //tamper
+		cursor := search.Page.Cursor
+		for _, bad := range []string{"xxx", "a.b", cursor[:len(cursor)-2] + "AA", strings.Replace(cursor, cursor[:2], "WW", 1)} {
+			search.Page.Cursor = bad
+			err = QueryUnify(queryer, ctx, search)
+			if err != ErrCursorInvalid {
+				t.Error(bad, err)
+				return
+			}
+			err = QueryRowUnifyTarget(queryer, ctx, search, "Query")
+			if err != ErrCursorInvalid {
+				t.Error(bad, err)
+				return
+			}
+		}
+		c := NewCRUD(DialectPostgres)
+		c.CursorKey = []byte("other")
+		search.Page.Cursor = cursor
+		err = c.QueryUnify(queryer, ctx, search)
+		if err != ErrCursorInvalid {
+			t.Error(err)
+			return
+		}
+	}
+	{ //key missing
+		c := NewCRUD(DialectPostgres)
+		err = c.QueryUnify(queryer, ctx, search)
+		if err != ErrCursorKeyMissing {
+			t.Error(err)
+			return
+		}
+		search.Page.Cursor = ""
+		queryer.Total = 2
+		err = c.QueryUnify(queryer, ctx, search)
+		if err != ErrCursorKeyMissing {
+			t.Error(err)
+			return
+		}
+		queryer.Total = 1
+		err = c.QueryUnify(queryer, ctx, search)
+		if err != nil || len(search.Page.NextCursor) > 0 {
+			t.Error(err, search.Page.NextCursor)
+			return
+		}
+	}
+	{ //time precision
+		c := NewCRUD(DialectPostgres)
+		c.CursorKey = []byte("test")
+		keyset := &keysetMeta{tag: "create_time,tid", columns: []string{"create_time", "tid"}}
+		createTime := time.Date(2023, 6, 1, 10, 20, 30, 123456000, time.UTC)
+		object := &CrudObject{TID: 10, CreateTime: xsql.Time(createTime)}
+		cursor, err := c.keysetCursor(keyset, reflect.ValueOf(object))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		keyset.model = reflect.ValueOf(object).Elem()
+		values, err := c.keysetValues(keyset, cursor)
+		if err != nil || len(values) != 2 || !time.Time(*values[0].(*xsql.Time)).Equal(createTime) || *values[1].(*int64) != 10 {
+			t.Error(err, values)
+			return
+		}
+	}
+	{ //asc
+		c := NewCRUD(DialectSQLite)
+		c.CursorKey = []byte("test")
+		keyset := &keysetMeta{tag: "o.tid#asc", columns: []string{"o.tid"}}
+		object := &CrudObject{TID: 10}
+		cursor, err := c.keysetCursor(keyset, reflect.ValueOf(object))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		keyset.model = reflect.ValueOf(object).Elem()
+		values, err := c.keysetValues(keyset, cursor)
+		if err != nil || len(values) != 1 || *values[0].(*int64) != 10 || keyset.orderby() != "order by o.tid asc" {
+			t.Error(err, values)
+			return
+		}
+	}
+}
+
+func TestKeysetQuery(t *testing.T) {
+	clearPG()
+	testKeysetQuery(t, getPG())
+}
+
+func testKeysetQuery(t *testing.T, queryer Queryer) {
+	Default.CursorKey = []byte("test")
+	defer func() { Default.CursorKey = nil }()
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		object := newTestObject()
+		object.UserID = 100
+		_, err := InsertFilter(queryer, ctx, object, "^tid#all", "returning", "tid#all")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	search := &KeysetCrudObjectUnify{}
+	search.Where.UserID = 100
+	search.Page.Limit = 2
+	tids := []int64{}
+	for i := 0; i < 5; i++ {
+		search.Query.Objects = nil
+		err := QueryUnify(queryer, ctx, search)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		for _, object := range search.Query.Objects {
+			tids = append(tids, object.TID)
+		}
+		if len(search.Page.NextCursor) < 1 {
+			break
+		}
+		search.Page.Cursor = search.Page.NextCursor
+	}
+	if len(tids) != 5 {
+		t.Error(tids)
+		return
+	}
+	for i := 1; i < len(tids); i++ {
+		if tids[i] >= tids[i-1] {
+			t.Error(tids)
+			return
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+)
+
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+	LogLevelOff
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	case LogLevelOff:
+		return "off"
+	default:
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+}
+
+// Logger is the structured logger, keyvals is the key/value pairs of op,table,sql,args,duration,rows,error
+type Logger interface {
+	LogFields(ctx context.Context, level LogLevel, msg string, keyvals ...interface{})
+}
+
+type LoggerF func(ctx context.Context, level LogLevel, msg string, keyvals ...interface{})
+
+func (l LoggerF) LogFields(ctx context.Context, level LogLevel, msg string, keyvals ...interface{}) {
+	l(ctx, level, msg, keyvals...)
+}
+
+// StdLogger is the Logger which output key=value to log.Printf
+var StdLogger = LoggerF(func(ctx context.Context, level LogLevel, msg string, keyvals ...interface{}) {
+	line := fmt.Sprintf("[%v] %v", level, msg)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		switch value := keyvals[i+1].(type) {
+		case string:
+			line += fmt.Sprintf(" %v=%q", keyvals[i], value)
+		case error:
+			line += fmt.Sprintf(" %v=%q", keyvals[i], value.Error())
+		case time.Duration, int64, nil:
+			line += fmt.Sprintf(" %v=%v", keyvals[i], value)
+		default:
+			line += fmt.Sprintf(" %v=%v", keyvals[i], jsonString(value))
+		}
+	}
+	log.Print(line)
+})
+
+func (c *CRUD) newEvent(op string, v interface{}, sql string, args []interface{}) (event *Event) {
+	event = &Event{Op: op, Model: reflect.TypeOf(v), SQL: sql}
+	event.Args, event.Redacted = c.unwrapArgs(v, args)
+	if v == nil {
+		return
+	}
+	if _, ok := v.([]interface{}); ok {
+		event.Table = c.Table(v)
+		return
+	}
+	reflectValue := reflect.Indirect(reflect.ValueOf(v))
+	if reflectValue.Kind() != reflect.Struct {
+		return
+	}
+	event.Table = c.Table(v)
+	if modelValue := reflectValue.FieldByName("Model"); len(event.Table) < 1 && modelValue.IsValid() && modelValue.Kind() == reflect.Struct {
+		event.Table = c.Table(modelValue.Addr().Interface())
+	}
+	return
+}
+
+// logEvent will log the event by LogLevel and SlowThreshold, error is logged on error level, slow is logged on warn level, other is debug level,
+// the no rows error of QueryRow is not failure, it is logged as other
+func (c *CRUD) logEvent(ctx context.Context, event *Event, err error) {
+	if c.Logger == nil {
+		return
+	}
+	level, msg := LogLevelDebug, "CRUD exec sql"
+	if err != nil && !c.isNoRows(err) {
+		level, msg = LogLevelError, "CRUD exec sql fail"
+	} else if c.SlowThreshold > 0 && event.Duration >= c.SlowThreshold {
+		level, msg = LogLevelWarn, "CRUD exec sql slow"
+	}
+	if level < c.LogLevel {
+		return
+	}
+	c.Logger.LogFields(ctx, level, msg,
+		"op", event.Op,
+		"table", event.Table,
+		"sql", event.SQL,
+		"args", event.RedactedArgs(),
+		"duration", event.Duration,
+		"rows", event.Affected,
+		"error", err,
+	)
+}
+
+// logRows will count the scanned rows and log the event on closing
+type logRows struct {
+	Rows
+	c      *CRUD
+	ctx    context.Context
+	event  *Event
+	begin  time.Time
+	closed bool
+}
+
+func (l *logRows) Next() (ok bool) {
+	ok = l.Rows.Next()
+	if ok {
+		l.event.Affected++
+	}
+	return
+}
+
+func (l *logRows) Close() (err error) {
+	err = l.Rows.Close()
+	if !l.closed {
+		l.closed = true
+		l.event.Duration = time.Since(l.begin)
+		l.c.logEvent(l.ctx, l.event, nil)
+	}
+	return
+}
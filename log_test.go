@@ -0,0 +1,125 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type logEntry struct {
+	Level  LogLevel
+	Msg    string
+	Fields map[string]interface{}
+}
+
+func TestLogger(t *testing.T) {
+	ctx := context.Background()
+	queryer := &eventQueryer{}
+	entries := []*logEntry{}
+	c := NewCRUD(DialectPostgres)
+	c.Logger = LoggerF(func(ctx context.Context, level LogLevel, msg string, keyvals ...interface{}) {
+		entry := &logEntry{Level: level, Msg: msg, Fields: map[string]interface{}{}}
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			entry.Fields[keyvals[i].(string)] = keyvals[i+1]
+		}
+		entries = append(entries, entry)
+		StdLogger.LogFields(ctx, level, msg, keyvals...)
+	})
+	{ //debug
+		c.DeleteWheref(queryer, ctx, &CrudObject{}, "tid=$%v", 1)
+		entry := entries[len(entries)-1]
+		if len(entries) != 1 || entry.Level != LogLevelDebug || entry.Fields["op"] != OpDelete || entry.Fields["table"] != "crud_object" || entry.Fields["rows"] != int64(2) || entry.Fields["error"] != nil {
+			t.Error(entry)
+			return
+		}
+		var objects []*CrudObject
+		err := c.QueryWheref(queryer, ctx, &CrudObject{}, "#all", "tid=$%v", []interface{}{1}, "", 0, 0, &objects)
+		entry = entries[len(entries)-1]
+		if err != nil || len(entries) != 2 || entry.Fields["op"] != OpQuery || entry.Fields["rows"] != int64(0) {
+			t.Error(err, entry)
+			return
+		}
+		_, err = NewRepo[CrudObject](c).Count(ctx, queryer, "tid=$%v", 1)
+		entry = entries[len(entries)-1]
+		if err != nil || len(entries) != 3 || entry.Fields["op"] != OpCount || entry.Fields["rows"] != int64(1) {
+			t.Error(err, entry)
+			return
+		}
+		unify := &DeleteCrudObjectUnify{}
+		unify.Where.TID = 1
+		_, err = c.DeleteUnify(queryer, ctx, unify)
+		entry = entries[len(entries)-1]
+		if err != nil || len(entries) != 4 || entry.Fields["op"] != OpDelete || entry.Fields["table"] != "crud_object" {
+			t.Error(err, entry)
+			return
+		}
+	}
+	{ //slow
+		entries = nil
+		c.LogLevel = LogLevelWarn
+		c.SlowThreshold = time.Hour
+		c.DeleteWheref(queryer, ctx, &CrudObject{}, "tid=$%v", 1)
+		if len(entries) != 0 {
+			t.Error(entries)
+			return
+		}
+		c.SlowThreshold = time.Nanosecond
+		c.Interceptors = []Interceptor{
+			func(ctx context.Context, event *Event, next EventHandler) error {
+				time.Sleep(time.Millisecond)
+				return next(ctx, event)
+			},
+		}
+		c.DeleteWheref(queryer, ctx, &CrudObject{}, "tid=$%v", 1)
+		if len(entries) != 1 || entries[0].Level != LogLevelWarn {
+			t.Error(entries)
+			return
+		}
+	}
+	{ //error
+		entries = nil
+		c.LogLevel = LogLevelError
+		c.Interceptors = []Interceptor{
+			func(ctx context.Context, event *Event, next EventHandler) error {
+				return fmt.Errorf("deny")
+			},
+		}
+		c.DeleteWheref(queryer, ctx, &CrudObject{}, "tid=$%v", 1)
+		if len(entries) != 1 || entries[0].Level != LogLevelError || entries[0].Fields["error"] == nil {
+			t.Error(entries)
+			return
+		}
+		c.LogLevel = LogLevelOff
+		c.DeleteWheref(queryer, ctx, &CrudObject{}, "tid=$%v", 1)
+		if len(entries) != 1 {
+			t.Error(entries)
+			return
+		}
+	}
+	{ //no rows
+		entries = nil
+		c.LogLevel = LogLevelDebug
+		c.SlowThreshold = 0
+		c.Interceptors = nil
+		queryer := &noRowsQueryer{Err: ErrNoRows}
+		_, err := NewRepo[CrudObject](c).Count(ctx, queryer, "tid=$%v", 1)
+		if err != ErrNoRows || len(entries) != 1 || entries[0].Level != LogLevelDebug || entries[0].Fields["error"] != ErrNoRows {
+			t.Error(err, entries)
+			return
+		}
+		entries = nil
+		queryer.Err = fmt.Errorf("other")
+		NewRepo[CrudObject](c).Count(ctx, queryer, "tid=$%v", 1)
+		if len(entries) != 1 || entries[0].Level != LogLevelError {
+			t.Error(entries)
+			return
+		}
+	}
+	for _, level := range []LogLevel{LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError, LogLevelOff, 100} {
+		if len(level.String()) < 1 {
+			t.Error(level)
+			return
+		}
+	}
+}
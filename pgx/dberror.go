@@ -0,0 +1,31 @@
+package pgx
+
+import (
+	"errors"
+
+	"github.com/codingeasygo/crud"
+	"github.com/jackc/pgconn"
+)
+
+func init() {
+	crud.RegisterClassifier(ClassifyError)
+}
+
+// ClassifyError will classify *pgconn.PgError and pgconn timeout to crud.DBError
+func ClassifyError(err error) (dbErr *crud.DBError) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		dbErr = &crud.DBError{
+			Kind:       crud.SQLStateKind(pgErr.Code),
+			Constraint: pgErr.ConstraintName,
+			Table:      pgErr.TableName,
+			Column:     pgErr.ColumnName,
+			Err:        err,
+		}
+		return
+	}
+	if pgconn.Timeout(err) {
+		dbErr = &crud.DBError{Kind: crud.Timeout, Err: err}
+	}
+	return
+}
@@ -0,0 +1,28 @@
+package pgx
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/codingeasygo/crud"
+	"github.com/jackc/pgconn"
+)
+
+func TestClassifyError(t *testing.T) {
+	err := fmt.Errorf("insert fail:%w", &pgconn.PgError{Code: "23503", ConstraintName: "crud_object_user_id_fkey", TableName: "crud_object", ColumnName: "user_id"})
+	dbErr := crud.ClassifyError(err)
+	if dbErr.Kind != crud.ForeignKeyViolation || dbErr.Constraint != "crud_object_user_id_fkey" || dbErr.Table != "crud_object" || dbErr.Column != "user_id" {
+		t.Error(dbErr)
+		return
+	}
+	_, _, err = Pool().Exec(context.Background(), "insert into crud_object(tid,title) values(null,'a')")
+	if dbErr = crud.ClassifyError(err); dbErr.Kind != crud.NotNullViolation {
+		t.Error(dbErr)
+		return
+	}
+	if dbErr = ClassifyError(fmt.Errorf("xxx")); dbErr != nil {
+		t.Error(dbErr)
+		return
+	}
+}
@@ -2,6 +2,7 @@ package pgx
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/codingeasygo/crud"
 	"github.com/jackc/pgconn"
@@ -132,6 +133,14 @@ func (t *Tx) Rollback(ctx context.Context) error {
 	return t.Tx.Rollback(ctx)
 }
 
+func (t *Tx) CrudCommit(ctx context.Context) error {
+	return t.Commit(ctx)
+}
+
+func (t *Tx) CrudRollback(ctx context.Context) error {
+	return t.Rollback(ctx)
+}
+
 func (t *Tx) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
 	if err := mockerCheck("Tx.CopyFrom", ""); err != nil {
 		return 0, err
@@ -323,6 +332,38 @@ func (p *PgQueryer) Begin(ctx context.Context) (tx *Tx, err error) {
 	return
 }
 
+func (p *PgQueryer) CrudBegin(ctx context.Context, opts *sql.TxOptions) (tx crud.CrudTx, err error) {
+	if err := mockerCheck("Pool.Begin", ""); err != nil {
+		return nil, err
+	}
+	raw, err := p.Pool.BeginTx(ctx, TxOptions(opts))
+	if err == nil {
+		tx = &Tx{Tx: raw}
+	}
+	return
+}
+
+// TxOptions will convert sql.TxOptions to pgx.TxOptions
+func TxOptions(opts *sql.TxOptions) (options pgx.TxOptions) {
+	if opts == nil {
+		return
+	}
+	switch opts.Isolation {
+	case sql.LevelReadUncommitted:
+		options.IsoLevel = pgx.ReadUncommitted
+	case sql.LevelReadCommitted:
+		options.IsoLevel = pgx.ReadCommitted
+	case sql.LevelRepeatableRead, sql.LevelSnapshot:
+		options.IsoLevel = pgx.RepeatableRead
+	case sql.LevelSerializable, sql.LevelLinearizable:
+		options.IsoLevel = pgx.Serializable
+	}
+	if opts.ReadOnly {
+		options.AccessMode = pgx.ReadOnly
+	}
+	return
+}
+
 func Exec(ctx context.Context, sql string, args ...interface{}) (insertId, affected int64, err error) {
 	return Shared.Exec(ctx, sql, args...)
 }
@@ -342,3 +383,8 @@ func Query(ctx context.Context, sql string, args ...interface{}) (rows crud.Rows
 func Begin(ctx context.Context) (tx *Tx, err error) {
 	return Shared.Begin(ctx)
 }
+
+func WithTx(ctx context.Context, opts *sql.TxOptions, call func(ctx context.Context, tx crud.Queryer) error) (err error) {
+	err = crud.WithTx(ctx, Pool, opts, call)
+	return
+}
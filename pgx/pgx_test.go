@@ -2,6 +2,7 @@ package pgx
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/codingeasygo/crud"
 	"github.com/codingeasygo/crud/gen"
 	"github.com/codingeasygo/crud/testsql"
 	"github.com/codingeasygo/util/converter"
@@ -191,6 +193,36 @@ func TestQueryer(t *testing.T) {
 	tx.QueryRow(context.Background(), "select 1")
 }
 
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+	err := WithTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable}, func(ctx context.Context, tx crud.Queryer) error {
+		_, _, err := tx.Exec(ctx, "update crud_object set status=0 where 1=0")
+		if err != nil {
+			return err
+		}
+		return crud.WithTx(ctx, Pool(), nil, func(ctx context.Context, tx crud.Queryer) error {
+			_, _, err := tx.Exec(ctx, "update crud_object set status=0 where 1=0")
+			return err
+		})
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = WithTx(ctx, &sql.TxOptions{ReadOnly: true}, func(ctx context.Context, tx crud.Queryer) error {
+		_, _, err := tx.Exec(ctx, "update crud_object set status=0 where 1=0")
+		return err
+	})
+	if err == nil {
+		t.Error(err)
+		return
+	}
+	for _, level := range []sql.IsolationLevel{sql.LevelDefault, sql.LevelReadUncommitted, sql.LevelReadCommitted, sql.LevelSnapshot, sql.LevelLinearizable} {
+		TxOptions(&sql.TxOptions{Isolation: level})
+	}
+	TxOptions(nil)
+}
+
 func TestMocker(t *testing.T) {
 	MockerStart()
 	defer MockerStop()
@@ -34,3 +34,32 @@ type CrudQueryer interface {
 	CrudQuery(ctx context.Context, query string, args ...interface{}) (rows Rows, err error)
 	CrudQueryRow(ctx context.Context, query string, args ...interface{}) (row Row)
 }
+
+type queryerContextKey struct{}
+
+// WithQueryer will return the context which carries queryer, all crud function called by the context will prefer this queryer
+func WithQueryer(ctx context.Context, queryer interface{}) context.Context {
+	return context.WithValue(ctx, queryerContextKey{}, queryer)
+}
+
+// QueryerFrom will return the queryer carried by ctx or fallback if not found
+func QueryerFrom(ctx context.Context, fallback interface{}) (queryer interface{}) {
+	queryer = fallback
+	if ctx == nil {
+		return
+	}
+	if having := ctx.Value(queryerContextKey{}); having != nil {
+		queryer = having
+	}
+	return
+}
+
+type CrudTx interface {
+	Queryer
+	CrudCommit(ctx context.Context) error
+	CrudRollback(ctx context.Context) error
+}
+
+type CrudBeginner interface {
+	CrudBegin(ctx context.Context, opts *sql.TxOptions) (tx CrudTx, err error)
+}
@@ -0,0 +1,180 @@
+package crud
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// RedactMask is the mask of redacted arg on logging
+var RedactMask = "******"
+
+var redactIdentRegexp = regexp.MustCompile(`[\w.]+`)
+
+// redactedArg is the arg of redacted column, it is marked when building args and unwrapped before passing to driver,
+// it is also driver.Valuer for the args which is passed to driver by caller directly
+type redactedArg struct {
+	Arg interface{}
+}
+
+func (r redactedArg) Value() (driver.Value, error) {
+	if valuer, ok := r.Arg.(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return driver.DefaultParameterConverter.ConvertValue(r.Arg)
+}
+
+func (r redactedArg) String() string {
+	return RedactMask
+}
+
+func (r redactedArg) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RedactMask)
+}
+
+// redactField will return if the column should be redacted by redact:"true" tag or CRUD.RedactColumns
+func (c *CRUD) redactField(fieldName string, field reflect.StructField) bool {
+	if field.Tag.Get("redact") == "true" {
+		return true
+	}
+	if parts := strings.SplitN(fieldName, ".", 2); len(parts) > 1 {
+		fieldName = parts[1]
+	}
+	for _, column := range c.RedactColumns {
+		if column == fieldName {
+			return true
+		}
+	}
+	return false
+}
+
+// redactArg will mark arg as redacted when the column should be redacted, or return arg
+func (c *CRUD) redactArg(fieldName string, field reflect.StructField, arg interface{}) interface{} {
+	if c.redactField(fieldName, field) {
+		return redactedArg{Arg: arg}
+	}
+	return arg
+}
+
+// redactFormat will mark arg as redacted when any column in format like lower(o.password)=$%v is in CRUD.RedactColumns,
+// the redact:"true" tag is not supported by format because there is no struct field
+func (c *CRUD) redactFormat(format string, arg interface{}) interface{} {
+	if len(c.RedactColumns) < 1 {
+		return arg
+	}
+	for _, ident := range redactIdentRegexp.FindAllString(format, -1) {
+		if c.redactField(ident, reflect.StructField{}) {
+			return redactedArg{Arg: arg}
+		}
+	}
+	return arg
+}
+
+// unwrapArgs will return the raw args which is passed to driver and the index of redacted arg,
+// the arg which is marked when building or equal to the redacted field value of v is redacted,
+// args is returned directly when nothing is marked
+func (c *CRUD) unwrapArgs(v interface{}, args []interface{}) (raws []interface{}, redacted []int) {
+	raws = args
+	for i, arg := range args {
+		if arg, ok := arg.(redactedArg); ok {
+			if &raws[0] == &args[0] {
+				raws = append([]interface{}{}, args...)
+			}
+			raws[i] = arg.Arg
+			redacted = append(redacted, i)
+		}
+	}
+	values := c.redactValues(v)
+	if len(values) < 1 {
+		return
+	}
+	for i, arg := range raws {
+		if redactContains(redacted, i) {
+			continue
+		}
+		argValue := reflect.ValueOf(arg)
+		for argValue.Kind() == reflect.Ptr && !argValue.IsNil() {
+			argValue = argValue.Elem()
+		}
+		if !argValue.IsValid() || argValue.IsZero() {
+			continue
+		}
+		for _, value := range values {
+			if reflect.DeepEqual(argValue.Interface(), value) {
+				redacted = append(redacted, i)
+				break
+			}
+		}
+	}
+	return
+}
+
+// stripArgs will return the raw args which is passed to driver when the redacted index is not needed
+func (c *CRUD) stripArgs(args []interface{}) (raws []interface{}) {
+	raws, _ = c.unwrapArgs(nil, args)
+	return
+}
+
+// redactValues will return the non-zero value of redacted field on v, it is used to redact the arg which is built by caller
+func (c *CRUD) redactValues(v interface{}) (values []interface{}) {
+	if v == nil {
+		return
+	}
+	reflectValue := reflect.ValueOf(v)
+	if reflectValue.Kind() != reflect.Ptr || reflectValue.IsNil() || reflectValue.Elem().Kind() != reflect.Struct {
+		return
+	}
+	reflectValue = reflectValue.Elem()
+	meta := c.loadFilterMeta("query", reflectValue.Type(), "#all")
+	for _, field := range meta.Fields {
+		if !c.redactField(field.Name, field.Field) {
+			continue
+		}
+		fieldValue := reflectValue.FieldByIndex(field.Index)
+		for fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() {
+			fieldValue = fieldValue.Elem()
+		}
+		if fieldValue.IsValid() && !fieldValue.IsZero() {
+			values = append(values, fieldValue.Interface())
+		}
+	}
+	return
+}
+
+func redactContains(indexes []int, index int) bool {
+	for _, i := range indexes {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
+
+// redactMask will return the copy of args which the arg of redacted index is masked, args is returned directly when nothing is redacted
+func redactMask(args []interface{}, redacted []int) (masked []interface{}) {
+	masked = args
+	if len(redacted) < 1 {
+		return
+	}
+	masked = append([]interface{}{}, args...)
+	for _, index := range redacted {
+		if index >= 0 && index < len(masked) {
+			masked[index] = RedactMask
+		}
+	}
+	return
+}
+
+// redactArgs will return the copy of args which the arg of redacted column is masked, it is used on verbose logging
+func (c *CRUD) redactArgs(v interface{}, args []interface{}) (masked []interface{}) {
+	raws, redacted := c.unwrapArgs(v, args)
+	masked = redactMask(raws, redacted)
+	return
+}
+
+// RedactedArgs will return the copy of Args which the arg of Redacted index is masked, it should be used when logging Args on interceptor
+func (e *Event) RedactedArgs() []interface{} {
+	return redactMask(e.Args, e.Redacted)
+}
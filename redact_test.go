@@ -0,0 +1,121 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type RedactObject struct {
+	T        string `table:"crud_object"`
+	TID      int64  `json:"tid"`
+	Title    string `json:"title"`
+	Password string `json:"password" redact:"true"`
+}
+
+type RedactWhere struct {
+	Password string `json:"password" cmp:"password=md5($%v)" redact:"true"`
+	Title    string `json:"title" cmp:"lower(title)=$%v"`
+	Status   string `json:"status" cmp:"$%v=status"`
+	TID      int64  `json:"tid"`
+}
+
+func TestRedact(t *testing.T) {
+	object := &RedactObject{TID: 100, Title: "title", Password: "secret"}
+	assertRedact := func(c *CRUD, v interface{}, args []interface{}, count int) {
+		raws, redacted := c.unwrapArgs(v, args)
+		if !strings.Contains(jsonString(raws), "secret") || len(redacted) != count {
+			t.Errorf("args %v is not raw or redacted %v", jsonString(raws), redacted)
+		}
+		masked := c.redactArgs(v, args)
+		if strings.Contains(jsonString(masked), "secret") || strings.Contains(fmt.Sprintf("%v", args), "secret") || strings.Count(jsonString(masked), RedactMask) != count {
+			t.Errorf("args %v is not redacted", jsonString(masked))
+		}
+	}
+	{ //tag
+		sql, args := InsertSQL(object, "tid,title,password")
+		assertRedact(Default, object, args, 1)
+		sql, args = UpdateSQL(object, "title,password", nil)
+		assertRedact(Default, object, args, 1)
+		if !strings.Contains(sql, "password=$2") {
+			t.Error(sql)
+			return
+		}
+		where, args := Default.FilterWhere(nil, &RedactWhere{Password: "secret", Title: "title", TID: 1}, "password,title,tid")
+		assertRedact(Default, nil, args, 1)
+		if strings.Join(where, " and ") != "password=md5($1) and lower(title)=$2 and tid = $3" {
+			t.Error(where)
+			return
+		}
+	}
+	{ //columns
+		c := NewCRUD(DialectPostgres)
+		c.RedactColumns = []string{"title", "status"}
+		where, args := c.FilterWhere(nil, &RedactWhere{Title: "secret", Status: "secret", TID: 1}, "title,status,tid")
+		assertRedact(c, nil, args, 2)
+		if strings.Join(where, " and ") != "lower(title)=$1 and $2=status and tid = $3" {
+			t.Error(where)
+			return
+		}
+		where, args = c.AppendWheref(nil, nil, "md5(o.title)=$%v,tid=$%v", "secret", 1)
+		assertRedact(c, nil, args, 1)
+		where, args = c.AppendWhere(where, args, true, "$%v=status", "secret")
+		assertRedact(c, nil, args, 2)
+		fields, _, args := c.AppendInsertf(nil, nil, nil, "title=$%v,tid=$%v", "secret", 1)
+		assertRedact(c, nil, args, 1)
+		if len(fields) != 2 {
+			t.Error(fields)
+			return
+		}
+		sets, args := c.AppendSet(nil, nil, true, "title=lower($%v)", "secret")
+		assertRedact(c, nil, args, 1)
+		if len(sets) != 1 {
+			t.Error(sets)
+			return
+		}
+		if value, err := (redactedArg{Arg: 1}).Value(); err != nil || value != int64(1) {
+			t.Error(value, err)
+			return
+		}
+	}
+	{ //event
+		c := NewCRUD(DialectPostgres)
+		c.RedactColumns = []string{"title"}
+		var events []*Event
+		c.Interceptors = append(c.Interceptors, func(ctx context.Context, event *Event, next EventHandler) error {
+			events = append(events, event)
+			return next(ctx, event)
+		})
+		queryer := &eventQueryer{}
+		affected, err := c.InsertBatch(queryer, context.Background(), []*RedactObject{{TID: 100, Title: "secret", Password: "secret"}, {TID: 101, Title: "secret"}}, "tid,title,password#all", 0, "")
+		if err != nil || affected != 2 || len(events) != 1 || len(events[0].Redacted) != 4 || strings.Contains(jsonString(events[0].RedactedArgs()), "secret") || !strings.Contains(jsonString(queryer.Args[0]), "secret") {
+			t.Error(err, affected, events)
+			return
+		}
+		//the format is redacted by RedactColumns only
+		err = c.QueryWheref(queryer, context.Background(), &RedactObject{}, "#all", "md5(password)=$%v,lower(title)=$%v", []interface{}{"secret", "secret"}, "", 0, 0, &[]*RedactObject{})
+		event := events[len(events)-1]
+		if err != nil || jsonString(event.Redacted) != "[1]" || jsonString(event.RedactedArgs()) != `["secret","******"]` {
+			t.Error(err, event.Redacted, jsonString(event.RedactedArgs()))
+			return
+		}
+		//the arg built by caller is redacted by the value of redacted field
+		err = c.UpdateRow(queryer, context.Background(), object, "update crud_object set password=md5($1)", []string{"tid=$2"}, "and", []interface{}{"secret", 100})
+		event = events[len(events)-1]
+		if err != nil || jsonString(event.Redacted) != "[0]" || jsonString(event.RedactedArgs()) != `["******",100]` || jsonString(queryer.Args[len(queryer.Args)-1]) != `["secret",100]` {
+			t.Error(err, event.Redacted, jsonString(event.RedactedArgs()))
+			return
+		}
+		var logged []interface{}
+		c.Logger = LoggerF(func(ctx context.Context, level LogLevel, msg string, keyvals ...interface{}) {
+			logged = append(logged, keyvals...)
+		})
+		c.Verbose = true
+		c.InsertFilter(queryer, context.Background(), object, "tid,title,password", "", "")
+		if strings.Contains(fmt.Sprintf("%v", logged), "secret") || !strings.Contains(fmt.Sprintf("%v", logged), RedactMask) || !strings.Contains(jsonString(queryer.Args[len(queryer.Args)-1]), "secret") {
+			t.Error(logged)
+			return
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package crud
+
+import (
+	"context"
+	"reflect"
+)
+
+// Repo is the typed repository of struct T, all query result is *T
+type Repo[T any] struct {
+	CRUD   *CRUD
+	Filter string
+}
+
+// NewRepo will create typed repository of T by c, Default is used when c is nil
+func NewRepo[T any](c *CRUD) (repo *Repo[T]) {
+	if c == nil {
+		c = Default
+	}
+	repo = &Repo[T]{
+		CRUD:   c,
+		Filter: "#all",
+	}
+	return
+}
+
+func (r *Repo[T]) querySQL(caller int, ctx context.Context, formats string, args []interface{}, orderby string, offset, limit int) (sql string, sqlArgs []interface{}, err error) {
+	sql = r.CRUD.querySQL(caller+1, new(T), "", r.Filter)
+	sql, sqlArgs, err = r.CRUD.joinWherefScope(caller+1, ctx, new(T), sql, nil, formats, args...)
+	sql = r.CRUD.joinPage(caller+1, sql, orderby, offset, limit)
+	return
+}
+
+func (r *Repo[T]) Find(ctx context.Context, queryer interface{}, formats string, args ...interface{}) (v *T, err error) {
+	defer r.CRUD.recoverError(&err)
+	v, err = r.find(1, ctx, queryer, formats, args...)
+	return
+}
+
+func (r *Repo[T]) find(caller int, ctx context.Context, queryer interface{}, formats string, args ...interface{}) (v *T, err error) {
+	c := r.CRUD
+	sql, sqlArgs, err := r.querySQL(caller+1, ctx, formats, args, "", 0, 0)
+	if err != nil {
+		return
+	}
+	v = new(T)
+	err = c.queryerQueryRow(queryer, ctx, OpQuery, v, sql, sqlArgs).Scan(c.ScanArgs(v, r.Filter)...)
+	if err != nil {
+		v = nil
+		if c.Verbose {
+			c.Log(caller, "CRUD repo find by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(v), sql, jsonString(c.redactArgs(new(T), sqlArgs)), err)
+		}
+		return
+	}
+	if c.Verbose {
+		c.Log(caller, "CRUD repo find by struct:%v,sql:%v,args:%v, result is success", reflect.TypeOf(v), sql, jsonString(c.redactArgs(new(T), sqlArgs)))
+	}
+	return
+}
+
+func (r *Repo[T]) List(ctx context.Context, queryer interface{}, orderby string, offset, limit int, formats string, args ...interface{}) (list []*T, err error) {
+	defer r.CRUD.recoverError(&err)
+	list = []*T{}
+	err = r.each(1, ctx, queryer, func(v *T) error {
+		list = append(list, v)
+		return nil
+	}, orderby, offset, limit, formats, args...)
+	return
+}
+
+func (r *Repo[T]) Each(ctx context.Context, queryer interface{}, call func(v *T) error, formats string, args ...interface{}) (err error) {
+	defer r.CRUD.recoverError(&err)
+	err = r.each(1, ctx, queryer, call, "", 0, 0, formats, args...)
+	return
+}
+
+func (r *Repo[T]) each(caller int, ctx context.Context, queryer interface{}, call func(v *T) error, orderby string, offset, limit int, formats string, args ...interface{}) (err error) {
+	c := r.CRUD
+	sql, sqlArgs, err := r.querySQL(caller+1, ctx, formats, args, orderby, offset, limit)
+	if err != nil {
+		return
+	}
+	rows, err := c.queryerQuery(queryer, ctx, OpQuery, new(T), sql, sqlArgs)
+	if err != nil {
+		if c.Verbose {
+			c.Log(caller, "CRUD repo query by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(new(T)), sql, jsonString(c.redactArgs(new(T), sqlArgs)), err)
+		}
+		return
+	}
+	defer rows.Close()
+	if c.Verbose {
+		c.Log(caller, "CRUD repo query by struct:%v,sql:%v,args:%v, result is success", reflect.TypeOf(new(T)), sql, jsonString(c.redactArgs(new(T), sqlArgs)))
+	}
+	for rows.Next() {
+		v := new(T)
+		err = rows.Scan(c.ScanArgs(v, r.Filter)...)
+		if err != nil {
+			break
+		}
+		err = call(v)
+		if err != nil {
+			break
+		}
+	}
+	return
+}
+
+func (r *Repo[T]) Count(ctx context.Context, queryer interface{}, formats string, args ...interface{}) (count int64, err error) {
+	c := r.CRUD
+	defer c.recoverError(&err)
+	sql := c.countSQL(1, new(T), "", "count(*)#all")
+	sql, sqlArgs, err := c.joinWherefScope(1, ctx, new(T), sql, nil, formats, args...)
+	if err != nil {
+		return
+	}
+	err = c.queryerQueryRow(queryer, ctx, OpCount, new(T), sql, sqlArgs).Scan(&count)
+	if err != nil {
+		if c.Verbose {
+			c.Log(1, "CRUD repo count by struct:%v,sql:%v,args:%v, result is fail:%v", reflect.TypeOf(new(T)), sql, jsonString(c.redactArgs(new(T), sqlArgs)), err)
+		}
+		return
+	}
+	if c.Verbose {
+		c.Log(1, "CRUD repo count by struct:%v,sql:%v,args:%v, result is success", reflect.TypeOf(new(T)), sql, jsonString(c.redactArgs(new(T), sqlArgs)))
+	}
+	return
+}
+
+func (r *Repo[T]) Insert(ctx context.Context, queryer interface{}, v *T, filter interface{}, join string, scan interface{}) (insertId int64, err error) {
+	defer r.CRUD.recoverError(&err)
+	insertId, err = r.CRUD.insertFilter(1, queryer, ctx, v, filterString(filter), join, filterString(scan))
+	return
+}
+
+func (r *Repo[T]) Update(ctx context.Context, queryer interface{}, v *T, filter interface{}, formats string, args ...interface{}) (affected int64, err error) {
+	defer r.CRUD.recoverError(&err)
+	affected, err = r.CRUD.updateWheref(1, queryer, ctx, v, filterString(filter), formats, args...)
+	return
+}
+
+func (r *Repo[T]) Delete(ctx context.Context, queryer interface{}, formats string, args ...interface{}) (affected int64, err error) {
+	defer r.CRUD.recoverError(&err)
+	affected, err = r.CRUD.deleteWheref(1, queryer, ctx, new(T), formats, args...)
+	return
+}
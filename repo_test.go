@@ -0,0 +1,125 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestRepo(t *testing.T) {
+	clearPG()
+	testRepo(t, getPG())
+}
+
+func testRepo(t *testing.T, queryer Queryer) {
+	ctx := context.Background()
+	repo := NewRepo[CrudObject](nil)
+	object := newTestObject()
+	object.UserID = 100
+	_, err := repo.Insert(ctx, queryer, object, "^tid#all", "returning", "tid#all")
+	if err != nil || object.TID < 1 {
+		t.Error(err)
+		return
+	}
+	for i := 0; i < 3; i++ {
+		item := newTestObject()
+		item.UserID = 100
+		_, err = repo.Insert(ctx, queryer, item, MustCompileFilter(item, "^tid#all"), "returning", "tid#all")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	{ //find
+		found, err := repo.Find(ctx, queryer, "tid=$%v", object.TID)
+		if err != nil || found.TID != object.TID || found.Title != object.Title {
+			t.Error(err, found)
+			return
+		}
+		_, err = repo.Find(ctx, queryer, "tid=$%v", -1)
+		if err != ErrNoRows {
+			t.Error(err)
+			return
+		}
+	}
+	{ //list
+		list, err := repo.List(ctx, queryer, "order by tid asc", 0, 2, "user_id=$%v", 100)
+		if err != nil || len(list) != 2 || list[0].TID != object.TID {
+			t.Error(err, list)
+			return
+		}
+		list, err = repo.List(ctx, queryer, "", 0, 0, "user_id=$%v", -1)
+		if err != nil || list == nil || len(list) != 0 {
+			t.Error(err, list)
+			return
+		}
+	}
+	{ //each
+		count := 0
+		err = repo.Each(ctx, queryer, func(v *CrudObject) error {
+			count++
+			return nil
+		}, "user_id=$%v", 100)
+		if err != nil || count != 4 {
+			t.Error(err, count)
+			return
+		}
+		stop := fmt.Errorf("stop")
+		err = repo.Each(ctx, queryer, func(v *CrudObject) error {
+			return stop
+		}, "user_id=$%v", 100)
+		if err != stop {
+			t.Error(err)
+			return
+		}
+	}
+	{ //count
+		count, err := repo.Count(ctx, queryer, "user_id=$%v", 100)
+		if err != nil || count != 4 {
+			t.Error(err, count)
+			return
+		}
+	}
+	{ //update
+		object.Title = "updated"
+		affected, err := repo.Update(ctx, queryer, object, "title", "tid=$%v", object.TID)
+		if err != nil || affected != 1 {
+			t.Error(err)
+			return
+		}
+		found, err := repo.Find(ctx, queryer, "tid=$%v", object.TID)
+		if err != nil || found.Title != "updated" {
+			t.Error(err, found)
+			return
+		}
+	}
+	{ //delete
+		affected, err := repo.Delete(ctx, queryer, "tid=$%v", object.TID)
+		if err != nil || affected != 1 {
+			t.Error(err)
+			return
+		}
+		count, err := repo.Count(ctx, queryer, "user_id=$%v", 100)
+		if err != nil || count != 3 {
+			t.Error(err, count)
+			return
+		}
+	}
+	{ //error
+		_, err = repo.Count(ctx, queryer, "xxx=$%v", 1)
+		if err == nil {
+			t.Error(err)
+			return
+		}
+		_, err = repo.List(ctx, queryer, "", 0, 0, "xxx=$%v", 1)
+		if err == nil {
+			t.Error(err)
+			return
+		}
+		found, err := repo.Find(ctx, queryer, "xxx=$%v", 1)
+		if err == nil || found != nil {
+			t.Error(err)
+			return
+		}
+	}
+}
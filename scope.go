@@ -0,0 +1,88 @@
+package crud
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ScopeFunc will append the predicate of named scope to where, the placeholder should be calculated by len(args) like AppendWheref
+type ScopeFunc func(where []string, args []interface{}) (where_ []string, args_ []interface{})
+
+var scopeLock = sync.RWMutex{}
+var scopeAll = map[reflect.Type]map[string]ScopeFunc{}
+
+// RegisterScope will register named scope on model, it is used by WithScope or scope:"name" tag on Unify Where
+func RegisterScope(model interface{}, name string, scope ScopeFunc) {
+	scopeLock.Lock()
+	defer scopeLock.Unlock()
+	modelType := reflect.Indirect(reflect.ValueOf(model)).Type()
+	if scopeAll[modelType] == nil {
+		scopeAll[modelType] = map[string]ScopeFunc{}
+	}
+	scopeAll[modelType][name] = scope
+}
+
+// LoadScope will return the named scope registered on model
+func LoadScope(model interface{}, name string) (scope ScopeFunc, ok bool) {
+	reflectValue := reflect.Indirect(reflect.ValueOf(model))
+	if !reflectValue.IsValid() {
+		return
+	}
+	scopeLock.RLock()
+	defer scopeLock.RUnlock()
+	scope, ok = scopeAll[reflectValue.Type()][name]
+	return
+}
+
+type scopeContextKey struct{}
+
+// WithScope will return the context having named scope, the scope is applied to model on Query*/Count*/Update*/Delete*/Unify
+func WithScope(ctx context.Context, names ...string) context.Context {
+	if len(names) < 1 {
+		return ctx
+	}
+	scopes := append(append([]string{}, ScopeFrom(ctx)...), names...)
+	return context.WithValue(ctx, scopeContextKey{}, scopes)
+}
+
+// ScopeFrom will return the named scope by WithScope
+func ScopeFrom(ctx context.Context) (names []string) {
+	if ctx == nil {
+		return
+	}
+	names, _ = ctx.Value(scopeContextKey{}).([]string)
+	return
+}
+
+// namedWhere will append the predicate of named scope in ctx to where, ErrUnknownScope is returned when scope is not registered on v
+func (c *CRUD) namedWhere(ctx context.Context, v interface{}, where []string, args []interface{}) (where_ []string, args_ []interface{}, err error) {
+	where_, args_ = where, args
+	for _, name := range ScopeFrom(ctx) {
+		scope, ok := LoadScope(v, name)
+		if !ok {
+			err = &ErrUnknownScope{Type: reflect.TypeOf(v), Name: name}
+			return
+		}
+		where_, args_ = scope(where_, args_)
+	}
+	return
+}
+
+// unifyScope will return the context having named scope by scope:"name" tag on unify Where
+func (c *CRUD) unifyScope(ctx context.Context, v interface{}, enabled ...string) context.Context {
+	reflectType := reflect.Indirect(reflect.ValueOf(v)).Type()
+	if len(enabled) < 1 {
+		enabled = append(enabled, "Where")
+	}
+	for _, key := range enabled {
+		whereType, _ := reflectType.FieldByName(key)
+		for _, name := range strings.Split(whereType.Tag.Get("scope"), ",") {
+			if name = strings.TrimSpace(name); len(name) > 0 {
+				ctx = WithScope(ctx, name)
+			}
+		}
+	}
+	return ctx
+}
@@ -0,0 +1,155 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type ScopeCrudObject struct {
+	T      string           `json:"-" table:"crud_object"`
+	TID    int64            `json:"tid,omitempty"`
+	UserID int64            `json:"user_id,omitempty"`
+	Type   string           `json:"type,omitempty"`
+	Title  string           `json:"title,omitempty"`
+	Status CrudObjectStatus `json:"status,omitempty"`
+}
+
+type ScopeCrudObjectUnify struct {
+	Model ScopeCrudObject `json:"model"`
+	Where struct {
+		UserID int64  `json:"user_id"`
+		Title  string `json:"title" cmp:"title like $%v"`
+	} `json:"where" join:"or" scope:"visible"`
+	Query struct {
+		Objects []*ScopeCrudObject `json:"objects"`
+	} `json:"query" filter:"#all"`
+	Count struct {
+		All int64 `json:"all" scan:"tid"`
+	} `json:"count" filter:"count(tid)#all"`
+}
+
+func init() {
+	RegisterScope(&ScopeCrudObject{}, "visible", func(where []string, args []interface{}) ([]string, []interface{}) {
+		return AppendWheref(where, args, "status=any($%v)", CrudObjectStatusShow)
+	})
+	RegisterScope(ScopeCrudObject{}, "typeA", func(where []string, args []interface{}) ([]string, []interface{}) {
+		return AppendWheref(where, args, "type=$%v", CrudObjectTypeA)
+	})
+}
+
+func TestScope(t *testing.T) {
+	queryer := &eventQueryer{}
+	object := &ScopeCrudObject{TID: 1, Title: "abc"}
+	lastSQL := func() string { return queryer.SQL[len(queryer.SQL)-1] }
+	ctx := WithScope(context.Background(), "visible")
+	{ //unknown
+		var scopeErr *ErrUnknownScope
+		err := QueryWheref(queryer, WithScope(context.Background(), "xxx"), object, "#all", "tid=$%v", []interface{}{1}, "", 0, 0)
+		if !errors.As(err, &scopeErr) || scopeErr.Name != "xxx" {
+			t.Error(err)
+			return
+		}
+		_, err = UpdateWheref(queryer, ctx, &CrudObject{TID: 1, Title: "abc"}, "title", "tid=$%v", 1)
+		if !errors.As(err, &scopeErr) {
+			t.Error(err)
+			return
+		}
+		if len(queryer.SQL) > 0 {
+			t.Error(queryer.SQL)
+			return
+		}
+		if _, ok := LoadScope(object, "visible"); !ok {
+			t.Error("not registered")
+			return
+		}
+	}
+	{ //query
+		QueryWheref(queryer, ctx, object, "tid#all", "tid=$%v,title=$%v#all,+or", []interface{}{1, "a"}, "", 0, 0)
+		if sql := lastSQL(); sql != "select tid from crud_object where (tid=$1 or title=$2) and status=any($3)" {
+			t.Error(sql)
+			return
+		}
+		QueryFilter(queryer, WithScope(ctx, "typeA"), object, "tid#all", nil, "", nil, "", 0, 0)
+		if sql := lastSQL(); sql != "select tid from crud_object where status=any($1) and type=$2" {
+			t.Error(sql)
+			return
+		}
+		var count int64
+		CountWheref(queryer, ctx, object, "count(tid)#all", "tid=$%v", []interface{}{1}, "", &count, "tid")
+		if sql := lastSQL(); sql != "select count(tid) from crud_object where (tid=$1) and status=any($2)" {
+			t.Error(sql)
+			return
+		}
+		NewRepo[ScopeCrudObject](nil).Find(WithScope(context.Background(), "typeA"), queryer, "tid=$%v", 1)
+		if sql := lastSQL(); sql != "select tid,user_id,type,title,status from crud_object where (tid=$1) and type=$2" {
+			t.Error(sql)
+			return
+		}
+	}
+	{ //update
+		UpdateWheref(queryer, ctx, object, "title", "tid=$%v", 1)
+		if sql := lastSQL(); sql != "update crud_object set title=$1 where (tid=$2) and status=any($3)" {
+			t.Error(sql)
+			return
+		}
+		UpdateFilter(queryer, ctx, object, "title", []string{"tid=$1", "title=$2"}, "or", []interface{}{1, "a"})
+		if sql := lastSQL(); sql != "update crud_object set title=$3 where (tid=$1 or title=$2) and status=any($4)" {
+			t.Error(sql)
+			return
+		}
+		DeleteWheref(queryer, ctx, object, "tid=$%v", 1)
+		if sql := lastSQL(); sql != "delete from crud_object where (tid=$1) and status=any($2)" {
+			t.Error(sql)
+			return
+		}
+	}
+	{ //unify
+		search := &ScopeCrudObjectUnify{}
+		search.Where.UserID = 100
+		search.Where.Title = "a%"
+		QueryUnify(queryer, context.Background(), search)
+		if sql := lastSQL(); sql != "select tid,user_id,type,title,status from crud_object where (user_id = $1 or title like $2) and status=any($3) " {
+			t.Error(sql)
+			return
+		}
+		CountUnify(queryer, WithScope(context.Background(), "typeA"), search)
+		if sql := lastSQL(); sql != "select count(tid) from crud_object where (user_id = $1 or title like $2) and type=$3 and status=any($4) " {
+			t.Error(sql)
+			return
+		}
+	}
+}
+
+func TestScopeQuery(t *testing.T) {
+	clearPG()
+	testScopeQuery(t, getPG())
+}
+
+func testScopeQuery(t *testing.T, queryer Queryer) {
+	ctx := context.Background()
+	for _, objectType := range []CrudObjectType{CrudObjectTypeA, CrudObjectTypeB} {
+		object := newTestObject()
+		object.Type = objectType
+		_, err := InsertFilter(queryer, ctx, object, "^tid#all", "returning", "tid#all")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	repo := NewRepo[ScopeCrudObject](nil)
+	list, err := repo.List(WithScope(ctx, "typeA"), queryer, "", 0, 0, "")
+	if err != nil || len(list) != 1 || list[0].Type != string(CrudObjectTypeA) {
+		t.Error(err, list)
+		return
+	}
+	affected, err := UpdateWheref(queryer, WithScope(ctx, "typeA"), &ScopeCrudObject{Title: "scoped"}, "title", "")
+	if err != nil || affected != 1 {
+		t.Error(err, affected)
+		return
+	}
+	if count, err := repo.Count(ctx, queryer, "title=$%v", "scoped"); err != nil || count != 1 {
+		t.Error(err, count)
+		return
+	}
+}
@@ -0,0 +1,66 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type unscopedContextKey struct{}
+
+// Unscoped will return the context which is skipping softdelete, the removed row is queried and delete is real delete
+func Unscoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedContextKey{}, true)
+}
+
+// IsUnscoped will return if ctx is returned by Unscoped
+func IsUnscoped(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	unscoped, _ := ctx.Value(unscopedContextKey{}).(bool)
+	return unscoped
+}
+
+// softDelete will parse the softdelete:"status=-1" tag on model, empty column is returned when not softdelete model or unscoped
+func (c *CRUD) softDelete(ctx context.Context, v interface{}) (column, value string) {
+	if IsUnscoped(ctx) {
+		return
+	}
+	tag := c.modelTag(v, "softdelete")
+	parts := strings.SplitN(tag, "=", 2)
+	if len(parts) < 2 {
+		return
+	}
+	column, value = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	return
+}
+
+// softWhere will append the predicate to skip removed row when v is softdelete model
+func (c *CRUD) softWhere(ctx context.Context, v interface{}, where []string) (where_ []string) {
+	where_ = where
+	column, value := c.softDelete(ctx, v)
+	if len(column) < 1 {
+		return
+	}
+	where_ = append(where_, fmt.Sprintf("%v<>%v", c.quote(column), value))
+	return
+}
+
+// deleteSoftSQL will return update sql to mark row removed when v is softdelete model, else return delete sql
+func (c *CRUD) deleteSoftSQL(caller int, ctx context.Context, v interface{}) (sql string) {
+	column, value := c.softDelete(ctx, v)
+	if len(column) < 1 {
+		sql = c.deleteSQL(caller+1, v)
+		return
+	}
+	if parts := strings.SplitN(column, ".", 2); len(parts) > 1 {
+		column = parts[1]
+	}
+	sql = fmt.Sprintf(`update %v set %v=%v`, c.Table(v), c.quote(column), value)
+	if c.Verbose {
+		c.Log(caller, "CRUD generate soft delete sql by struct:%v, result is sql:%v", reflect.TypeOf(v), sql)
+	}
+	return
+}
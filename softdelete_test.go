@@ -0,0 +1,173 @@
+package crud
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type SoftCrudObject struct {
+	T      string           `json:"-" table:"crud_object" softdelete:"status=-1"`
+	TID    int64            `json:"tid,omitempty"`
+	UserID int64            `json:"user_id,omitempty"`
+	Title  string           `json:"title,omitempty"`
+	Status CrudObjectStatus `json:"status,omitempty"`
+}
+
+type SoftCrudObjectUnify struct {
+	Model SoftCrudObject `json:"model"`
+	Where struct {
+		UserID int64  `json:"user_id"`
+		Title  string `json:"title" cmp:"title like $%v"`
+	} `json:"where" join:"or"`
+	Query struct {
+		Objects []*SoftCrudObject `json:"objects"`
+	} `json:"query" filter:"#all"`
+	Count struct {
+		All int64 `json:"all" scan:"tid"`
+	} `json:"count" filter:"count(tid)#all"`
+}
+
+func TestSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	queryer := &eventQueryer{}
+	object := &SoftCrudObject{TID: 1}
+	lastSQL := func() string { return queryer.SQL[len(queryer.SQL)-1] }
+	{ //delete
+		DeleteWheref(queryer, ctx, object, "tid=$%v", 1)
+		if sql := lastSQL(); sql != "update crud_object set status=-1 where (tid=$1) and status<>-1" {
+			t.Error(sql)
+			return
+		}
+		DeleteFilter(queryer, ctx, object, "tid", []string{"user_id=$1"}, "or", []interface{}{100})
+		if sql := lastSQL(); sql != "update crud_object set status=-1 where (user_id=$1 or tid = $2) and status<>-1" {
+			t.Error(sql)
+			return
+		}
+		DeleteWheref(queryer, Unscoped(ctx), object, "tid=$%v", 1)
+		if sql := lastSQL(); sql != "delete from crud_object where tid=$1" {
+			t.Error(sql)
+			return
+		}
+		DeleteWheref(queryer, ctx, &CrudObject{}, "tid=$%v", 1)
+		if sql := lastSQL(); sql != "delete from crud_object where tid=$1" {
+			t.Error(sql)
+			return
+		}
+	}
+	{ //query
+		QueryWheref(queryer, ctx, object, "tid#all", "tid=$%v,title=$%v#all,+or", []interface{}{1, "a"}, "", 0, 0)
+		if sql := lastSQL(); sql != "select tid from crud_object where (tid=$1 or title=$2) and status<>-1" {
+			t.Error(sql)
+			return
+		}
+		QueryWheref(queryer, ctx, object, "tid#all", "", nil, "", 0, 0)
+		if sql := lastSQL(); sql != "select tid from crud_object where status<>-1" {
+			t.Error(sql)
+			return
+		}
+		QueryRowWheref(queryer, Unscoped(ctx), object, "tid#all", "", nil)
+		if sql := lastSQL(); sql != "select tid from crud_object" {
+			t.Error(sql)
+			return
+		}
+		QueryFilter(queryer, ctx, object, "tid#all", nil, "", nil, "", 0, 0)
+		if sql := lastSQL(); sql != "select tid from crud_object where status<>-1" {
+			t.Error(sql)
+			return
+		}
+		var count int64
+		CountFilter(queryer, ctx, object, "count(tid)#all", []string{"user_id=$1"}, "and", []interface{}{100}, "", &count, "tid")
+		if sql := lastSQL(); sql != "select count(tid) from crud_object where (user_id=$1) and status<>-1 " {
+			t.Error(sql)
+			return
+		}
+		CountWheref(queryer, ctx, object, "count(tid)#all", "user_id=$%v", []interface{}{100}, "", &count, "tid")
+		if sql := lastSQL(); sql != "select count(tid) from crud_object where (user_id=$1) and status<>-1" {
+			t.Error(sql)
+			return
+		}
+		NewRepo[SoftCrudObject](nil).Find(ctx, queryer, "tid=$%v", 1)
+		if sql := lastSQL(); !strings.HasSuffix(sql, "from crud_object where (tid=$1) and status<>-1") {
+			t.Error(sql)
+			return
+		}
+	}
+	{ //unify
+		search := &SoftCrudObjectUnify{}
+		search.Where.UserID = 100
+		search.Where.Title = "a%"
+		QueryUnify(queryer, ctx, search)
+		if sql := lastSQL(); sql != "select tid,user_id,title,status from crud_object where (user_id = $1 or title like $2) and status<>-1 " {
+			t.Error(sql)
+			return
+		}
+		CountUnify(queryer, ctx, search)
+		if sql := lastSQL(); sql != "select count(tid) from crud_object where (user_id = $1 or title like $2) and status<>-1 " {
+			t.Error(sql)
+			return
+		}
+		DeleteUnify(queryer, ctx, search)
+		if sql := lastSQL(); sql != "update crud_object set status=-1 where (user_id = $1 or title like $2) and status<>-1" {
+			t.Error(sql)
+			return
+		}
+		DeleteUnify(queryer, Unscoped(ctx), search)
+		if sql := lastSQL(); sql != "delete from crud_object where user_id = $1  or title like $2" {
+			t.Error(sql)
+			return
+		}
+	}
+}
+
+func TestSoftDeleteQuery(t *testing.T) {
+	clearPG()
+	testSoftDeleteQuery(t, getPG())
+}
+
+func testSoftDeleteQuery(t *testing.T, queryer Queryer) {
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		object := newTestObject()
+		object.UserID = 200
+		_, err := InsertFilter(queryer, ctx, object, "^tid#all", "returning", "tid#all")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	repo := NewRepo[SoftCrudObject](nil)
+	list, err := repo.List(ctx, queryer, "order by tid asc", 0, 0, "user_id=$%v", 200)
+	if err != nil || len(list) != 2 {
+		t.Error(err, list)
+		return
+	}
+	affected, err := repo.Delete(ctx, queryer, "tid=$%v", list[0].TID)
+	if err != nil || affected != 1 {
+		t.Error(err, affected)
+		return
+	}
+	affected, err = repo.Delete(ctx, queryer, "tid=$%v", list[0].TID)
+	if err != nil || affected != 0 {
+		t.Error(err, affected)
+		return
+	}
+	if count, err := repo.Count(ctx, queryer, "user_id=$%v", 200); err != nil || count != 1 {
+		t.Error(err, count)
+		return
+	}
+	if count, err := repo.Count(Unscoped(ctx), queryer, "user_id=$%v", 200); err != nil || count != 2 {
+		t.Error(err, count)
+		return
+	}
+	removed, err := repo.Find(Unscoped(ctx), queryer, "tid=$%v", list[0].TID)
+	if err != nil || removed.Status != CrudObjectStatusRemoved {
+		t.Error(err, removed)
+		return
+	}
+	_, err = repo.Find(ctx, queryer, "tid=$%v", list[0].TID)
+	if err != ErrNoRows {
+		t.Error(err)
+		return
+	}
+}
@@ -0,0 +1,27 @@
+package sqlx
+
+import (
+	"errors"
+
+	"github.com/codingeasygo/crud"
+	"github.com/lib/pq"
+)
+
+func init() {
+	crud.RegisterClassifier(ClassifyPqError)
+}
+
+// ClassifyPqError will classify lib/pq *pq.Error to crud.DBError
+func ClassifyPqError(err error) (dbErr *crud.DBError) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		dbErr = &crud.DBError{
+			Kind:       crud.SQLStateKind(string(pqErr.Code)),
+			Constraint: pqErr.Constraint,
+			Table:      pqErr.Table,
+			Column:     pqErr.Column,
+			Err:        err,
+		}
+	}
+	return
+}
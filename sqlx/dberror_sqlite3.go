@@ -0,0 +1,52 @@
+//go:build cgo
+// +build cgo
+
+package sqlx
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codingeasygo/crud"
+	"github.com/mattn/go-sqlite3"
+)
+
+func init() {
+	crud.RegisterClassifier(ClassifySqlite3Error)
+}
+
+// ClassifySqlite3Error will classify mattn/go-sqlite3 sqlite3.Error to crud.DBError,
+// the table and column is parsed from message like "UNIQUE constraint failed: crud_object.title"
+func ClassifySqlite3Error(err error) (dbErr *crud.DBError) {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return
+	}
+	dbErr = &crud.DBError{Err: err}
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		dbErr.Kind = crud.UniqueViolation
+	case sqlite3.ErrConstraintForeignKey:
+		dbErr.Kind = crud.ForeignKeyViolation
+	case sqlite3.ErrConstraintNotNull:
+		dbErr.Kind = crud.NotNullViolation
+	case sqlite3.ErrConstraintCheck:
+		dbErr.Kind = crud.CheckViolation
+	default:
+		if sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked {
+			dbErr.Kind = crud.Timeout
+		}
+		return
+	}
+	parts := strings.SplitN(sqliteErr.Error(), "constraint failed: ", 2)
+	if len(parts) < 2 {
+		return
+	}
+	target := strings.TrimSpace(strings.SplitN(parts[1], ",", 2)[0])
+	if dbErr.Kind == crud.CheckViolation {
+		dbErr.Constraint = target
+	} else if field := strings.SplitN(target, ".", 2); len(field) > 1 {
+		dbErr.Table, dbErr.Column = field[0], field[1]
+	}
+	return
+}
@@ -0,0 +1,56 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/codingeasygo/crud"
+	"github.com/lib/pq"
+)
+
+func TestClassifyPqError(t *testing.T) {
+	err := fmt.Errorf("insert fail:%w", &pq.Error{Code: "23505", Constraint: "crud_object_title_key", Table: "crud_object"})
+	dbErr := crud.ClassifyError(err)
+	if dbErr.Kind != crud.UniqueViolation || dbErr.Constraint != "crud_object_title_key" || dbErr.Table != "crud_object" {
+		t.Error(dbErr)
+		return
+	}
+	if dbErr = ClassifyPqError(fmt.Errorf("xxx")); dbErr != nil {
+		t.Error(dbErr)
+		return
+	}
+}
+
+func TestClassifyErrorSQLITE(t *testing.T) {
+	ctx := context.Background()
+	queryer := getSQLITE()
+	_, _, err := queryer.Exec(ctx, `
+		drop table if exists crud_classify;
+		create table crud_classify(tid integer primary key, title text not null unique, level int not null check(level>0));
+		insert into crud_classify(tid,title,level) values(1,'a',1);
+	`)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for sql, expect := range map[string]crud.DBError{
+		"insert into crud_classify(tid,title,level) values(2,'a',1)":  {Kind: crud.UniqueViolation, Table: "crud_classify", Column: "title"},
+		"insert into crud_classify(tid,title,level) values(1,'b',1)":  {Kind: crud.UniqueViolation, Table: "crud_classify", Column: "tid"},
+		"insert into crud_classify(tid,title,level) values(3,null,1)": {Kind: crud.NotNullViolation, Table: "crud_classify", Column: "title"},
+		"insert into crud_classify(tid,title,level) values(4,'c',0)":  {Kind: crud.CheckViolation, Constraint: "level>0"},
+	} {
+		_, _, err = queryer.Exec(ctx, sql)
+		dbErr := crud.ClassifyError(err)
+		if dbErr == nil || dbErr.Kind != expect.Kind || dbErr.Table != expect.Table || dbErr.Column != expect.Column || dbErr.Constraint != expect.Constraint {
+			t.Error(sql, dbErr)
+			return
+		}
+	}
+	_, _, err = queryer.Exec(ctx, "select * from crud_classify_none")
+	if dbErr := crud.ClassifyError(err); dbErr.Kind != crud.UnknownError {
+		t.Error(dbErr)
+		return
+	}
+	queryer.Exec(ctx, "drop table crud_classify")
+}
@@ -13,14 +13,28 @@ var Pool = func() *DbQueryer {
 	return Shared
 }
 
+// Bootstrap will open the shared queryer and set the dialect of crud.Default by driverName
 func Bootstrap(driverName, dataSourceName string) (db *sql.DB, err error) {
 	db, err = sql.Open(driverName, dataSourceName)
 	if err == nil {
 		Shared = NewDbQueryer(db)
+		crud.Default.Dialect = Dialect(driverName)
 	}
 	return
 }
 
+// Dialect will return the crud dialect by database/sql driver name
+func Dialect(driverName string) crud.Dialect {
+	switch driverName {
+	case "mysql":
+		return crud.DialectMySQL
+	case "sqlite", "sqlite3":
+		return crud.DialectSQLite
+	default:
+		return crud.DialectPostgres
+	}
+}
+
 type Row struct {
 	SQL string
 	*sql.Row
@@ -89,6 +103,14 @@ func (t *TxQueryer) Rollback() error {
 	return t.Tx.Rollback()
 }
 
+func (t *TxQueryer) CrudCommit(ctx context.Context) error {
+	return t.Commit()
+}
+
+func (t *TxQueryer) CrudRollback(ctx context.Context) error {
+	return t.Rollback()
+}
+
 func (t *TxQueryer) Exec(ctx context.Context, query string, args ...interface{}) (insertId, affected int64, err error) {
 	if err := mockerCheck("Tx.Exec", ""); err != nil {
 		return 0, 0, err
@@ -162,6 +184,19 @@ func (d *DbQueryer) Begin(ctx context.Context) (tx *TxQueryer, err error) {
 	return
 }
 
+func (d *DbQueryer) CrudBegin(ctx context.Context, opts *sql.TxOptions) (tx crud.CrudTx, err error) {
+	if err := mockerCheck("Pool.Begin", ""); err != nil {
+		return nil, err
+	}
+	raw, err := d.DB.BeginTx(ctx, opts)
+	if err == nil {
+		queryer := NewTxQueryer(raw)
+		queryer.ErrNoRows = d.ErrNoRows
+		tx = queryer
+	}
+	return
+}
+
 func (d *DbQueryer) Exec(ctx context.Context, query string, args ...interface{}) (insertId, affected int64, err error) {
 	if err := mockerCheck("Pool.Exec", ""); err != nil {
 		return 0, 0, err
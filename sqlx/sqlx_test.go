@@ -3,6 +3,7 @@ package sqlx
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -423,3 +424,168 @@ func TestQueryerSQLITE(t *testing.T) {
 		tx.getErrNoRows()
 	}
 }
+
+type noReturningDialect struct {
+	crud.SQLiteDialect
+}
+
+func (noReturningDialect) Returning() bool { return false }
+
+type lastInsertObject struct {
+	T          string    `table:"crud_object"`
+	TID        int64     `json:"tid"`
+	Title      string    `json:"title"`
+	TimeValue  xsql.Time `json:"time_value"`
+	UpdateTime xsql.Time `json:"update_time"`
+	CreateTime xsql.Time `json:"create_time"`
+	Status     int       `json:"status"`
+}
+
+func newLastInsertObject() *lastInsertObject {
+	return &lastInsertObject{Title: "last", TimeValue: xsql.TimeNow(), UpdateTime: xsql.TimeNow(), CreateTime: xsql.TimeNow(), Status: 100}
+}
+
+func TestLastInsertIdSQLITE(t *testing.T) {
+	if Dialect("mysql") != crud.DialectMySQL || Dialect("sqlite3") != crud.DialectSQLite || Dialect("postgres") != crud.DialectPostgres {
+		t.Error("error")
+		return
+	}
+	shared, dialect := Shared, crud.Default.Dialect
+	if _, err := Bootstrap("sqlite3", ":memory:"); err != nil || crud.Default.Dialect != crud.DialectSQLite || Shared == shared {
+		t.Error(err)
+		return
+	}
+	Shared, crud.Default.Dialect = shared, dialect
+	c := crud.NewCRUD(noReturningDialect{})
+	c.Verbose = true
+	{
+		object := newLastInsertObject()
+		_, err := c.InsertFilter(getSQLITE(), context.Background(), object, "^tid#all", "returning", "tid#all")
+		if err != nil || object.TID < 1 {
+			t.Error(err)
+			return
+		}
+		object.Title = "upsert"
+		_, err = c.UpsertFilter(getSQLITE(), context.Background(), object, "#all", "tid", "title", "tid#all")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	{
+		objects := []*lastInsertObject{newLastInsertObject(), newLastInsertObject(), newLastInsertObject()}
+		//sqlite LastInsertId is the last row id of multi row insert, so using one row per chunk
+		affected, err := c.InsertBatch(getSQLITE(), context.Background(), objects, "^tid#all", 1, "tid#all")
+		if err != nil || affected != 3 || objects[0].TID < 1 || objects[1].TID != objects[0].TID+1 || objects[2].TID != objects[1].TID+1 {
+			t.Error(err, affected)
+			return
+		}
+	}
+	{ //error
+		object := newLastInsertObject()
+		_, err := c.InsertFilter(getSQLITE(), context.Background(), object, "^tid#all", "", "tid,title#all")
+		if err == nil {
+			t.Error(err)
+			return
+		}
+		_, err = c.InsertFilter(getSQLITE(), context.Background(), object, "^tid#all", "", "title#all")
+		if err == nil {
+			t.Error(err)
+			return
+		}
+	}
+}
+
+func testWithTx(t *testing.T, c *crud.CRUD, queryer *DbQueryer) {
+	ctx := context.Background()
+	insert := func(ctx context.Context, tx crud.Queryer, title string) {
+		object := newLastInsertObject()
+		object.Title = title
+		_, err := c.InsertFilter(tx, ctx, object, "^tid#all", "", "")
+		if err != nil {
+			panic(err)
+		}
+	}
+	count := func(title string) (having int64) {
+		err := queryer.QueryRow(ctx, "select count(*) from crud_object where title=$1", title).Scan(&having)
+		if err != nil {
+			panic(err)
+		}
+		return
+	}
+	{ //commit
+		err := c.WithTx(ctx, queryer, nil, func(ctx context.Context, tx crud.Queryer) error {
+			insert(ctx, tx, "tx_commit")
+			return nil
+		})
+		if err != nil || count("tx_commit") != 1 {
+			t.Error(err)
+			return
+		}
+	}
+	{ //rollback
+		err := c.WithTx(ctx, queryer, &sql.TxOptions{}, func(ctx context.Context, tx crud.Queryer) error {
+			insert(ctx, tx, "tx_rollback")
+			return fmt.Errorf("rollback")
+		})
+		if err == nil || count("tx_rollback") != 0 {
+			t.Error(err)
+			return
+		}
+	}
+	{ //panic
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("not panic")
+				}
+			}()
+			c.WithTx(ctx, queryer, nil, func(ctx context.Context, tx crud.Queryer) error {
+				insert(ctx, tx, "tx_panic")
+				panic("panic")
+			})
+		}()
+		if count("tx_panic") != 0 {
+			t.Error("error")
+			return
+		}
+	}
+	{ //nested
+		err := c.WithTx(ctx, queryer, nil, func(ctx context.Context, tx crud.Queryer) error {
+			insert(ctx, tx, "tx_outer")
+			err := c.WithTx(ctx, queryer, nil, func(ctx context.Context, tx crud.Queryer) error {
+				insert(ctx, tx, "tx_inner_rollback")
+				return fmt.Errorf("rollback")
+			})
+			if err == nil {
+				return fmt.Errorf("not error")
+			}
+			err = crud.WithTx(ctx, tx, nil, func(ctx context.Context, tx crud.Queryer) error {
+				return c.WithTx(ctx, queryer, nil, func(ctx context.Context, tx crud.Queryer) error {
+					insert(ctx, tx, "tx_inner_commit")
+					return nil
+				})
+			})
+			return err
+		})
+		if err != nil || count("tx_outer") != 1 || count("tx_inner_rollback") != 0 || count("tx_inner_commit") != 1 {
+			t.Error(err)
+			return
+		}
+	}
+	{ //error
+		err := c.WithTx(ctx, "xxx", nil, func(ctx context.Context, tx crud.Queryer) error { return nil })
+		if err == nil {
+			t.Error(err)
+			return
+		}
+	}
+}
+
+func TestWithTxPG(t *testing.T) {
+	testWithTx(t, crud.Default, getPG())
+}
+
+func TestWithTxSQLITE(t *testing.T) {
+	testWithTx(t, crud.NewCRUD(crud.DialectSQLite), getSQLITE())
+}
@@ -0,0 +1,134 @@
+package crud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrTenantMissing is returned when the model having tenant:"true" field is called without tenant in context
+var ErrTenantMissing = errors.New("tenant is missing in context")
+
+type tenantContextKey struct{}
+
+type withoutTenantContextKey struct{}
+
+// WithTenant will return the context having tenant id, it is used to scope the model having tenant:"true" field
+func WithTenant(ctx context.Context, id interface{}) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, id)
+}
+
+// TenantFrom will return the tenant id by WithTenant
+func TenantFrom(ctx context.Context) (id interface{}, ok bool) {
+	if ctx == nil {
+		return
+	}
+	id = ctx.Value(tenantContextKey{})
+	ok = id != nil
+	return
+}
+
+// WithoutTenant will return the context which is skipping tenant scope, it is used to opt out tenant checking explicitly
+func WithoutTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutTenantContextKey{}, true)
+}
+
+// IsWithoutTenant will return if ctx is returned by WithoutTenant
+func IsWithoutTenant(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	without, _ := ctx.Value(withoutTenantContextKey{}).(bool)
+	return without
+}
+
+// tenantField will return the field with tenant:"true" tag of v, nil is returned when v is not tenant model
+func (c *CRUD) tenantField(v interface{}) (field *fieldMeta, value reflect.Value) {
+	reflectValue := reflect.Indirect(reflect.ValueOf(v))
+	if reflectValue.Kind() != reflect.Struct {
+		return
+	}
+	meta := c.loadFilterMeta("query", reflectValue.Type(), "#all")
+	for _, f := range meta.Fields {
+		if f.Field.Tag.Get("tenant") == "true" {
+			field, value = f, reflectValue.FieldByIndex(f.Index)
+			return
+		}
+	}
+	return
+}
+
+// tenantWhere will append the tenant predicate to where when v is tenant model, ErrTenantMissing is returned when tenant is not in ctx
+func (c *CRUD) tenantWhere(ctx context.Context, v interface{}, where []string, args []interface{}) (where_ []string, args_ []interface{}, err error) {
+	where_, args_ = where, args
+	if IsWithoutTenant(ctx) {
+		return
+	}
+	field, _ := c.tenantField(v)
+	if field == nil {
+		return
+	}
+	id, ok := TenantFrom(ctx)
+	if !ok {
+		err = ErrTenantMissing
+		return
+	}
+	args_ = append(args_, id)
+	where_ = append(where_, fmt.Sprintf("%v=%v", c.quote(field.Name), c.placeholder(len(args_))))
+	return
+}
+
+// tenantFill will fill the tenant field of v by tenant in ctx when it is zero, error is returned when it is not matched to ctx
+func (c *CRUD) tenantFill(ctx context.Context, v interface{}) (err error) {
+	if IsWithoutTenant(ctx) {
+		return
+	}
+	field, value := c.tenantField(v)
+	if field == nil {
+		return
+	}
+	id, ok := TenantFrom(ctx)
+	if !ok {
+		err = ErrTenantMissing
+		return
+	}
+	idValue := reflect.ValueOf(id)
+	if !idValue.Type().ConvertibleTo(value.Type()) {
+		err = fmt.Errorf("tenant %v is not convertible to %v", id, value.Type())
+		return
+	}
+	idValue = idValue.Convert(value.Type())
+	if value.IsZero() {
+		if !value.CanSet() {
+			err = fmt.Errorf("tenant field %v is not settable", field.Name)
+			return
+		}
+		value.Set(idValue)
+		return
+	}
+	if !reflect.DeepEqual(value.Interface(), idValue.Interface()) {
+		err = fmt.Errorf("tenant field %v=%v is not matched to %v", field.Name, value.Interface(), id)
+		return
+	}
+	return
+}
+
+// tenantUpsert will return the where of on conflict do update to skip the conflict row of other tenant,
+// error is returned when the dialect is not supported the where like mysql
+func (c *CRUD) tenantUpsert(ctx context.Context, v interface{}, updateFilter string) (where string, err error) {
+	if IsWithoutTenant(ctx) || len(updateFilter) < 1 {
+		return
+	}
+	field, _ := c.tenantField(v)
+	if field == nil {
+		return
+	}
+	if c.Dialect != nil && c.Dialect.Name() == "mysql" {
+		err = fmt.Errorf("upsert on tenant model %v is not supported by %v, use WithoutTenant to skip", reflect.TypeOf(v), c.Dialect.Name())
+		return
+	}
+	column := c.quote(field.Name)
+	where = fmt.Sprintf("where %v.%v=excluded.%v", c.Table(v), column, column)
+	return
+}
@@ -0,0 +1,251 @@
+package crud
+
+import (
+	"context"
+	"testing"
+)
+
+type TenantCrudObject struct {
+	T      string           `json:"-" table:"crud_object"`
+	TID    int64            `json:"tid,omitempty"`
+	UserID int64            `json:"user_id,omitempty" tenant:"true"`
+	Title  string           `json:"title,omitempty"`
+	Status CrudObjectStatus `json:"status,omitempty"`
+}
+
+type TenantCrudObjectUnify struct {
+	Model TenantCrudObject `json:"model"`
+	Where struct {
+		TID   int64  `json:"tid"`
+		Title string `json:"title" cmp:"title like $%v"`
+	} `json:"where" join:"or"`
+	Query struct {
+		Objects []*TenantCrudObject `json:"objects"`
+	} `json:"query" filter:"#all"`
+	Count struct {
+		All int64 `json:"all" scan:"tid"`
+	} `json:"count" filter:"count(tid)#all"`
+}
+
+func TestTenant(t *testing.T) {
+	ctx := WithTenant(context.Background(), 100)
+	queryer := &eventQueryer{}
+	object := &TenantCrudObject{TID: 1, Title: "abc"}
+	lastSQL := func() string { return queryer.SQL[len(queryer.SQL)-1] }
+	{ //missing
+		err := QueryWheref(queryer, context.Background(), object, "#all", "tid=$%v", []interface{}{1}, "", 0, 0)
+		if err != ErrTenantMissing {
+			t.Error(err)
+			return
+		}
+		_, err = UpdateWheref(queryer, context.Background(), object, "title", "tid=$%v", 1)
+		if err != ErrTenantMissing {
+			t.Error(err)
+			return
+		}
+		_, err = InsertFilter(queryer, context.Background(), &TenantCrudObject{Title: "abc"}, "^tid#all", "", "")
+		if err != ErrTenantMissing {
+			t.Error(err)
+			return
+		}
+		_, err = DeleteUnify(queryer, context.Background(), &TenantCrudObjectUnify{})
+		if err != ErrTenantMissing {
+			t.Error(err)
+			return
+		}
+		if len(queryer.SQL) > 0 {
+			t.Error(queryer.SQL)
+			return
+		}
+		_, err = DeleteWheref(queryer, WithoutTenant(context.Background()), object, "tid=$%v", 1)
+		if err != nil || lastSQL() != "delete from crud_object where tid=$1" {
+			t.Error(err, lastSQL())
+			return
+		}
+	}
+	{ //query
+		QueryWheref(queryer, ctx, object, "tid#all", "tid=$%v,title=$%v#all,+or", []interface{}{1, "a"}, "", 0, 0)
+		if sql := lastSQL(); sql != "select tid from crud_object where (tid=$1 or title=$2) and user_id=$3" {
+			t.Error(sql)
+			return
+		}
+		QueryFilter(queryer, ctx, object, "tid#all", nil, "", nil, "", 0, 0)
+		if sql := lastSQL(); sql != "select tid from crud_object where user_id=$1" {
+			t.Error(sql)
+			return
+		}
+		var count int64
+		CountWheref(queryer, ctx, object, "count(tid)#all", "tid=$%v", []interface{}{1}, "", &count, "tid")
+		if sql := lastSQL(); sql != "select count(tid) from crud_object where (tid=$1) and user_id=$2" {
+			t.Error(sql)
+			return
+		}
+		NewRepo[TenantCrudObject](nil).Find(ctx, queryer, "tid=$%v", 1)
+		if sql := lastSQL(); sql != "select tid,user_id,title,status from crud_object where (tid=$1) and user_id=$2" {
+			t.Error(sql)
+			return
+		}
+		QueryWheref(queryer, ctx, object, "tid#all", "tid=$%v or user_id<>$%v", []interface{}{1}, "", 0, 0)
+		if sql := lastSQL(); sql != "select tid from crud_object where (tid=$1 or user_id<>$1) and user_id=$2" {
+			t.Error(sql)
+			return
+		}
+		QueryFilter(queryer, ctx, object, "tid#all", []string{"tid=$1 or user_id<>$2"}, "and", []interface{}{1, 100}, "", 0, 0)
+		if sql := lastSQL(); sql != "select tid from crud_object where (tid=$1 or user_id<>$2) and user_id=$3" {
+			t.Error(sql)
+			return
+		}
+		UpdateWheref(queryer, ctx, object, "title", "tid=$%v or user_id<>$%v", 1)
+		if sql := lastSQL(); sql != "update crud_object set title=$1 where (tid=$2 or user_id<>$2) and user_id=$3" {
+			t.Error(sql)
+			return
+		}
+		QueryWheref(queryer, WithoutTenant(ctx), object, "tid#all", "tid=$%v", []interface{}{1}, "", 0, 0)
+		if sql := lastSQL(); sql != "select tid from crud_object where tid=$1" {
+			t.Error(sql)
+			return
+		}
+	}
+	{ //update
+		UpdateWheref(queryer, ctx, object, "title", "tid=$%v", 1)
+		if sql := lastSQL(); sql != "update crud_object set title=$1 where (tid=$2) and user_id=$3" {
+			t.Error(sql)
+			return
+		}
+		UpdateFilter(queryer, ctx, object, "title", []string{"tid=$1", "title=$2"}, "or", []interface{}{1, "a"})
+		if sql := lastSQL(); sql != "update crud_object set title=$3 where (tid=$1 or title=$2) and user_id=$4" {
+			t.Error(sql)
+			return
+		}
+		UpdateSet(queryer, ctx, object, []string{"title=$1"}, []string{"tid=$2"}, "and", []interface{}{"a", 1})
+		if sql := lastSQL(); sql != "update crud_object set title=$1 where (tid=$2) and user_id=$3" {
+			t.Error(sql)
+			return
+		}
+	}
+	{ //delete
+		_, err := Delete(queryer, context.Background(), object, "delete from crud_object", []string{"tid=$1"}, "and", []interface{}{1})
+		if err != ErrTenantMissing {
+			t.Error(err)
+			return
+		}
+		Delete(queryer, ctx, object, "delete from crud_object", []string{"tid=$1", "title=$2"}, "or", []interface{}{1, "a"})
+		if sql := lastSQL(); sql != "delete from crud_object where (tid=$1 or title=$2) and user_id=$3" {
+			t.Error(sql)
+			return
+		}
+		DeleteRow(queryer, ctx, object, "delete from crud_object", []string{"tid=$1"}, "and", []interface{}{1})
+		if sql := lastSQL(); sql != "delete from crud_object where (tid=$1) and user_id=$2" {
+			t.Error(sql)
+			return
+		}
+	}
+	{ //unify
+		search := &TenantCrudObjectUnify{}
+		search.Where.TID = 1
+		search.Where.Title = "a%"
+		QueryUnify(queryer, ctx, search)
+		if sql := lastSQL(); sql != "select tid,user_id,title,status from crud_object where (tid = $1 or title like $2) and user_id=$3 " {
+			t.Error(sql)
+			return
+		}
+		CountUnify(queryer, ctx, search)
+		if sql := lastSQL(); sql != "select count(tid) from crud_object where (tid = $1 or title like $2) and user_id=$3 " {
+			t.Error(sql)
+			return
+		}
+		DeleteUnify(queryer, ctx, search)
+		if sql := lastSQL(); sql != "delete from crud_object where (tid = $1 or title like $2) and user_id=$3" {
+			t.Error(sql)
+			return
+		}
+	}
+	{ //insert
+		added := &TenantCrudObject{Title: "abc"}
+		_, err := InsertFilter(queryer, ctx, added, "^tid#all", "", "")
+		if err != nil || added.UserID != 100 {
+			t.Error(err, added)
+			return
+		}
+		_, err = InsertFilter(queryer, ctx, &TenantCrudObject{UserID: 200, Title: "abc"}, "^tid#all", "", "")
+		if err == nil {
+			t.Error(err)
+			return
+		}
+		_, err = InsertFilter(queryer, WithoutTenant(context.Background()), &TenantCrudObject{UserID: 200, Title: "abc"}, "^tid#all", "", "")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, err = UpsertFilter(queryer, ctx, &TenantCrudObject{TID: 1, Title: "abc"}, "tid,user_id,title", "tid", "title", "")
+		if sql := lastSQL(); err != nil || sql != "insert into crud_object(tid,user_id,title) values($1,$2,$3) on conflict (tid) do update set title=excluded.title where crud_object.user_id=excluded.user_id" {
+			t.Error(err, sql)
+			return
+		}
+		_, err = UpsertFilter(queryer, ctx, &TenantCrudObject{TID: 1, Title: "abc"}, "tid,user_id,title", "tid", "", "")
+		if sql := lastSQL(); err != nil || sql != "insert into crud_object(tid,user_id,title) values($1,$2,$3) on conflict (tid) do nothing" {
+			t.Error(err, sql)
+			return
+		}
+		mysql := NewCRUD(DialectMySQL)
+		_, err = mysql.UpsertFilter(queryer, ctx, &TenantCrudObject{TID: 1, Title: "abc"}, "tid,user_id,title", "tid", "title", "")
+		if err == nil {
+			t.Error(err)
+			return
+		}
+		batch := []*TenantCrudObject{{Title: "a"}, {Title: "b"}}
+		_, err = InsertBatch(queryer, ctx, batch, "^tid#all", 0, "")
+		if err != nil || batch[0].UserID != 100 || batch[1].UserID != 100 {
+			t.Error(err, batch)
+			return
+		}
+	}
+}
+
+func TestTenantQuery(t *testing.T) {
+	clearPG()
+	testTenantQuery(t, getPG())
+}
+
+func testTenantQuery(t *testing.T, queryer Queryer) {
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		object := newTestObject()
+		object.UserID = int64(300 + i%2)
+		_, err := InsertFilter(queryer, ctx, object, "^tid#all", "returning", "tid#all")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	repo := NewRepo[TenantCrudObject](nil)
+	tenantCtx := WithTenant(ctx, 300)
+	list, err := repo.List(tenantCtx, queryer, "order by tid asc", 0, 0, "")
+	if err != nil || len(list) != 2 {
+		t.Error(err, list)
+		return
+	}
+	other, err := repo.List(WithTenant(ctx, 301), queryer, "order by tid asc", 0, 0, "")
+	if err != nil || len(other) != 1 {
+		t.Error(err, other)
+		return
+	}
+	_, err = repo.Find(tenantCtx, queryer, "tid=$%v", other[0].TID)
+	if err != ErrNoRows {
+		t.Error(err)
+		return
+	}
+	affected, err := repo.Delete(tenantCtx, queryer, "tid=$%v", other[0].TID)
+	if err != nil || affected != 0 {
+		t.Error(err, affected)
+		return
+	}
+	if count, err := repo.Count(WithoutTenant(ctx), queryer, "user_id>=$%v", 300); err != nil || count != 3 {
+		t.Error(err, count)
+		return
+	}
+	if _, err = repo.Count(ctx, queryer, ""); err != ErrTenantMissing {
+		t.Error(err)
+		return
+	}
+}
@@ -0,0 +1,104 @@
+package crud
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+type txContextKey struct{}
+
+type txState struct {
+	Tx    CrudTx
+	Depth int
+}
+
+// TxFrom will return the transaction which is bound to ctx by WithTx
+func TxFrom(ctx context.Context) (tx CrudTx, ok bool) {
+	state, ok := ctx.Value(txContextKey{}).(*txState)
+	if ok {
+		tx = state.Tx
+	}
+	return
+}
+
+// WithTx will run call in transaction begin by queryer and commit when call return nil, rollback when call return error or panic.
+// nested call by ctx or by transaction queryer will run call in savepoint, opts is only used on beginning transaction.
+// the ctx passed to call is carrying the transaction by WithQueryer, so all crud function called by it will join the transaction.
+func WithTx(ctx context.Context, queryer interface{}, opts *sql.TxOptions, call func(ctx context.Context, tx Queryer) error) (err error) {
+	err = Default.withTx(1, ctx, queryer, opts, call)
+	return
+}
+
+func (c *CRUD) WithTx(ctx context.Context, queryer interface{}, opts *sql.TxOptions, call func(ctx context.Context, tx Queryer) error) (err error) {
+	err = c.withTx(1, ctx, queryer, opts, call)
+	return
+}
+
+func (c *CRUD) withTx(caller int, ctx context.Context, queryer interface{}, opts *sql.TxOptions, call func(ctx context.Context, tx Queryer) error) (err error) {
+	queryer = QueryerFrom(ctx, queryer)
+	reflectValue := reflect.ValueOf(queryer)
+	if reflectValue.Kind() == reflect.Func {
+		queryer = reflectValue.Call(nil)[0].Interface()
+	}
+	var tx CrudTx
+	depth := 0
+	if state, ok := ctx.Value(txContextKey{}).(*txState); ok {
+		tx, depth = state.Tx, state.Depth+1
+	} else if having, ok := queryer.(CrudTx); ok {
+		tx, depth = having, 1
+	}
+	savepoint := fmt.Sprintf("crud_sp_%v", depth)
+	if depth > 0 {
+		_, _, err = tx.Exec(ctx, "savepoint "+savepoint)
+	} else if beginner, ok := queryer.(CrudBeginner); ok {
+		tx, err = beginner.CrudBegin(ctx, opts)
+	} else {
+		err = fmt.Errorf("queryer %v is not supported to begin transaction", reflect.TypeOf(queryer))
+	}
+	if err != nil {
+		if c.Verbose {
+			c.Log(caller, "CRUD begin transaction by queryer:%v,depth:%v, result is fail:%v", reflect.TypeOf(queryer), depth, err)
+		}
+		return
+	}
+	defer func() {
+		if perr := recover(); perr != nil {
+			c.rollbackTx(caller+1, ctx, tx, depth, savepoint)
+			panic(perr)
+		}
+	}()
+	err = call(WithQueryer(context.WithValue(ctx, txContextKey{}, &txState{Tx: tx, Depth: depth}), tx), tx)
+	if err != nil {
+		c.rollbackTx(caller+1, ctx, tx, depth, savepoint)
+		return
+	}
+	if depth > 0 {
+		_, _, err = tx.Exec(ctx, "release savepoint "+savepoint)
+	} else {
+		err = tx.CrudCommit(ctx)
+	}
+	if err != nil {
+		if c.Verbose {
+			c.Log(caller, "CRUD commit transaction by queryer:%v,depth:%v, result is fail:%v", reflect.TypeOf(queryer), depth, err)
+		}
+		return
+	}
+	if c.Verbose {
+		c.Log(caller, "CRUD commit transaction by queryer:%v,depth:%v, result is success", reflect.TypeOf(queryer), depth)
+	}
+	return
+}
+
+func (c *CRUD) rollbackTx(caller int, ctx context.Context, tx CrudTx, depth int, savepoint string) {
+	var err error
+	if depth > 0 {
+		_, _, err = tx.Exec(ctx, "rollback to savepoint "+savepoint)
+	} else {
+		err = tx.CrudRollback(ctx)
+	}
+	if c.Verbose {
+		c.Log(caller, "CRUD rollback transaction by depth:%v, result is %v", depth, err)
+	}
+}
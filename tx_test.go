@@ -0,0 +1,116 @@
+package crud
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testTx struct {
+	*TestDbQueryer
+	SQL []string
+}
+
+func (t *testTx) Exec(ctx context.Context, query string, args ...interface{}) (insertId, affected int64, err error) {
+	t.SQL = append(t.SQL, query)
+	return
+}
+
+func (t *testTx) CrudCommit(ctx context.Context) error {
+	t.SQL = append(t.SQL, "commit")
+	return nil
+}
+
+func (t *testTx) CrudRollback(ctx context.Context) error {
+	t.SQL = append(t.SQL, "rollback")
+	return nil
+}
+
+type testBeginner struct {
+	Tx   *testTx
+	Opts *sql.TxOptions
+}
+
+func (t *testBeginner) CrudBegin(ctx context.Context, opts *sql.TxOptions) (tx CrudTx, err error) {
+	t.Opts = opts
+	t.Tx = &testTx{}
+	tx = t.Tx
+	return
+}
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+	beginner := &testBeginner{}
+	err := WithTx(ctx, func() interface{} { return beginner }, &sql.TxOptions{ReadOnly: true}, func(ctx context.Context, tx Queryer) error {
+		if having, ok := TxFrom(ctx); !ok || having != tx {
+			return fmt.Errorf("tx not found")
+		}
+		tx.Exec(ctx, "a")
+		Default.WithTx(ctx, beginner, nil, func(ctx context.Context, tx Queryer) error {
+			tx.Exec(ctx, "b")
+			return WithTx(ctx, beginner, nil, func(ctx context.Context, tx Queryer) error {
+				return fmt.Errorf("rollback")
+			})
+		})
+		return nil
+	})
+	sqls := strings.Join(beginner.Tx.SQL, ",")
+	if err != nil || !beginner.Opts.ReadOnly || sqls != "a,savepoint crud_sp_1,b,savepoint crud_sp_2,rollback to savepoint crud_sp_2,rollback to savepoint crud_sp_1,commit" {
+		t.Error(err, sqls)
+		return
+	}
+	tx := &testTx{}
+	err = WithTx(ctx, tx, nil, func(ctx context.Context, tx Queryer) error { return nil })
+	if sqls := strings.Join(tx.SQL, ","); err != nil || sqls != "savepoint crud_sp_1,release savepoint crud_sp_1" {
+		t.Error(err, sqls)
+		return
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("not panic")
+			}
+		}()
+		WithTx(ctx, beginner, nil, func(ctx context.Context, tx Queryer) error { panic("panic") })
+	}()
+	if sqls := strings.Join(beginner.Tx.SQL, ","); sqls != "rollback" {
+		t.Error(sqls)
+		return
+	}
+	if _, ok := TxFrom(ctx); ok {
+		t.Error("error")
+		return
+	}
+	err = WithTx(ctx, "xxx", nil, func(ctx context.Context, tx Queryer) error { return nil })
+	if err == nil {
+		t.Error(err)
+		return
+	}
+}
+
+func TestWithQueryer(t *testing.T) {
+	tx := &testTx{}
+	ctx := WithQueryer(context.Background(), tx)
+	affected, err := DeleteWheref("xxx", ctx, &CrudObject{}, "tid=$%v", 1)
+	if err != nil || affected != 0 || len(tx.SQL) != 1 || tx.SQL[0] != "delete from crud_object where tid=$1" {
+		t.Error(err, tx.SQL)
+		return
+	}
+	if QueryerFrom(ctx, nil) != tx || QueryerFrom(context.Background(), "a") != "a" || QueryerFrom(nil, "a") != "a" {
+		t.Error("error")
+		return
+	}
+	beginner := &testBeginner{}
+	err = WithTx(ctx, beginner, nil, func(ctx context.Context, tx Queryer) error {
+		if QueryerFrom(ctx, nil) != tx {
+			return fmt.Errorf("queryer is not tx")
+		}
+		return nil
+	})
+	if err != nil || beginner.Tx != nil || strings.Join(tx.SQL, ",") != "delete from crud_object where tid=$1,savepoint crud_sp_1,release savepoint crud_sp_1" {
+		t.Error(err, tx.SQL)
+		return
+	}
+}
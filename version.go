@@ -0,0 +1,66 @@
+package crud
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrStaleObject is returned when updating versioned model and no row is matched by the version, it is changed by other
+var ErrStaleObject = errors.New("stale object")
+
+// versionField will return the field with version:"true" tag of v, nil is returned when v is not versioned model
+func (c *CRUD) versionField(v interface{}) (field *fieldMeta, value reflect.Value) {
+	reflectValue := reflect.Indirect(reflect.ValueOf(v))
+	if reflectValue.Kind() != reflect.Struct {
+		return
+	}
+	meta := c.loadFilterMeta("update", reflectValue.Type(), "#all")
+	for _, f := range meta.Fields {
+		if f.Field.Tag.Get("version") != "true" {
+			continue
+		}
+		switch f.Field.Type.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			field, value = f, reflectValue.FieldByIndex(f.Index)
+		}
+		return
+	}
+	return
+}
+
+// versionWhere will append version=$n to where when v is versioned model, the where is grouped by parentheses
+func (c *CRUD) versionWhere(v interface{}, where []string, args []interface{}, sep string) (where_ []string, args_ []interface{}, sep_ string, versioned bool) {
+	where_, args_, sep_ = where, args, sep
+	field, value := c.versionField(v)
+	if field == nil {
+		return
+	}
+	args_ = append(args_, value.Interface())
+	where_, sep_ = c.groupWhere(where, sep, fmt.Sprintf("%v=%v", c.quote(field.Name), c.placeholder(len(args_))))
+	versioned = true
+	return
+}
+
+// versionCheck will return ErrStaleObject when no row is updated, else increase the version field of v
+func (c *CRUD) versionCheck(v interface{}, affected int64) (err error) {
+	if affected < 1 {
+		err = ErrStaleObject
+		return
+	}
+	c.versionNext(v)
+	return
+}
+
+// versionNext will increase the version field of v after updated
+func (c *CRUD) versionNext(v interface{}) {
+	field, value := c.versionField(v)
+	if field == nil || !value.CanSet() {
+		return
+	}
+	if value.CanUint() {
+		value.SetUint(value.Uint() + 1)
+	} else {
+		value.SetInt(value.Int() + 1)
+	}
+}
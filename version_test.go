@@ -0,0 +1,144 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+type VersionCrudObject struct {
+	T      string           `json:"-" table:"crud_object"`
+	TID    int64            `json:"tid,omitempty"`
+	UserID int64            `json:"user_id,omitempty"`
+	Title  string           `json:"title,omitempty"`
+	Level  int              `json:"level" version:"true"`
+	Status CrudObjectStatus `json:"status,omitempty"`
+}
+
+type versionQueryer struct {
+	eventQueryer
+	Args     [][]interface{}
+	Affected int64
+}
+
+func (v *versionQueryer) Exec(ctx context.Context, query string, args ...interface{}) (insertId, affected int64, err error) {
+	v.SQL = append(v.SQL, query)
+	v.Args = append(v.Args, args)
+	insertId, affected = 1, v.Affected
+	return
+}
+
+func TestVersion(t *testing.T) {
+	ctx := context.Background()
+	queryer := &versionQueryer{Affected: 1}
+	lastSQL := func() string { return queryer.SQL[len(queryer.SQL)-1] }
+	lastArg := func() string {
+		args := queryer.Args[len(queryer.Args)-1]
+		return fmt.Sprintf("%v", args[len(args)-1])
+	}
+	object := &VersionCrudObject{TID: 1, Title: "abc", Level: 3}
+	{ //args
+		_, sets, args := UpdateArgs(object, "title,level", nil)
+		if len(sets) != 2 || sets[0] != "title=$1" || sets[1] != "level=level+1" || len(args) != 1 {
+			t.Error(sets, args)
+			return
+		}
+		_, sets, _ = UpdateArgs(&CrudObject{Title: "abc", Level: 1}, "title,level", nil)
+		if len(sets) != 2 || sets[0] != "level=$1" {
+			t.Error(sets)
+			return
+		}
+	}
+	{ //update
+		_, err := UpdateWheref(queryer, ctx, object, "title", "tid=$%v", object.TID)
+		if err != nil || object.Level != 4 {
+			t.Error(err, object.Level)
+			return
+		}
+		if sql := lastSQL(); sql != "update crud_object set title=$1,level=level+1 where (tid=$2) and level=$3" || lastArg() != "3" {
+			t.Error(sql, lastArg())
+			return
+		}
+		err = UpdateRowFilter(queryer, ctx, object, "title", []string{"tid=$1", "user_id=$2"}, "or", []interface{}{1, 100})
+		if err != nil || object.Level != 5 {
+			t.Error(err, object.Level)
+			return
+		}
+		if sql := lastSQL(); sql != "update crud_object set title=$3,level=level+1 where (tid=$1 or user_id=$2) and level=$4" || lastArg() != "4" {
+			t.Error(sql, lastArg())
+			return
+		}
+		sql, args := UpdateSQL(object, "title", nil)
+		where, args := AppendWheref(nil, args, "tid=$%v", object.TID)
+		err = UpdateRow(queryer, ctx, object, sql, where, "and", args)
+		if err != nil || object.Level != 6 {
+			t.Error(err, object.Level)
+			return
+		}
+		if sql := lastSQL(); sql != "update crud_object set title=$1,level=level+1 where (tid=$2) and level=$3" {
+			t.Error(sql)
+			return
+		}
+	}
+	{ //stale
+		queryer.Affected = 0
+		err := UpdateRowWheref(queryer, ctx, object, "title", "tid=$%v", object.TID)
+		if err != ErrStaleObject || object.Level != 6 {
+			t.Error(err, object.Level)
+			return
+		}
+		_, err = UpdateFilter(queryer, ctx, object, "title", []string{"tid=$1"}, "and", []interface{}{1})
+		if err != ErrStaleObject || object.Level != 6 {
+			t.Error(err, object.Level)
+			return
+		}
+		err = UpdateRowWheref(queryer, ctx, &CrudObject{TID: 1, Title: "abc"}, "title", "tid=$%v", 1)
+		if err != ErrNoRows {
+			t.Error(err)
+			return
+		}
+	}
+}
+
+func TestVersionQuery(t *testing.T) {
+	clearPG()
+	testVersionQuery(t, getPG())
+}
+
+func testVersionQuery(t *testing.T, queryer Queryer) {
+	ctx := context.Background()
+	object := newTestObject()
+	_, err := InsertFilter(queryer, ctx, object, "^tid#all", "returning", "tid#all")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	repo := NewRepo[VersionCrudObject](nil)
+	first, err := repo.Find(ctx, queryer, "tid=$%v", object.TID)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	second, err := repo.Find(ctx, queryer, "tid=$%v", object.TID)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	first.Title = "first"
+	err = UpdateRowWheref(queryer, ctx, first, "title", "tid=$%v", first.TID)
+	if err != nil || first.Level != second.Level+1 {
+		t.Error(err, first.Level)
+		return
+	}
+	second.Title = "second"
+	err = UpdateRowWheref(queryer, ctx, second, "title", "tid=$%v", second.TID)
+	if err != ErrStaleObject {
+		t.Error(err)
+		return
+	}
+	found, err := repo.Find(ctx, queryer, "tid=$%v", object.TID)
+	if err != nil || found.Title != "first" || found.Level != first.Level {
+		t.Error(err, found)
+		return
+	}
+}